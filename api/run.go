@@ -93,6 +93,27 @@ func EnvoyVersionsURL(envoyVersionsURL string) RunOption {
 	}
 }
 
+// TrustedKey adds a minisign public key, such as the contents of "minisign.pub",
+// trusted to sign the Envoy versions JSON. This is in addition to any "*.pub"
+// files in "$ConfigHome/trusted-keys".
+//
+// When there are trusted keys, the signature at EnvoyVersionsURL plus ".sig"
+// is verified, if present. Sign with "minisign -S -l", as pre-hashed
+// signatures aren't supported.
+func TrustedKey(publicKey string) RunOption {
+	return func(o *api.RunOpts) {
+		o.TrustedKeys = append(o.TrustedKeys, publicKey)
+	}
+}
+
+// RequireSignature refuses an Envoy versions JSON that isn't signed by a
+// trusted key. See TrustedKey.
+func RequireSignature() RunOption {
+	return func(o *api.RunOpts) {
+		o.RequireSignature = true
+	}
+}
+
 // EnvoyVersion overrides the version of Envoy to run. Defaults to the
 // contents of "$ConfigHome/envoy-version".
 //
@@ -10,6 +10,7 @@ require (
 	github.com/ulikunitz/xz/v2 v2.0.0-dev.4
 	github.com/urfave/cli-docs/v3 v3.1.1-0.20251020103632-a70fcdc58fc8
 	github.com/urfave/cli/v3 v3.8.0
+	golang.org/x/sys v0.41.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -28,6 +29,5 @@ require (
 	github.com/tklauser/numcpus v0.11.0 // indirect
 	github.com/ulikunitz/lz v0.6.11 // indirect
 	github.com/yusufpapurcu/wmi v1.2.4 // indirect
-	golang.org/x/sys v0.41.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
@@ -230,6 +230,15 @@ LOOP:
 	return parseAdminPort(adminAddr)
 }
 
+// ReadAdminPort returns the port in an admin address file written by Envoy.
+func ReadAdminPort(adminAddressPath string) (int, error) {
+	data, err := os.ReadFile(adminAddressPath) //nolint:gosec // path comes from our own --admin-address-path flag
+	if err != nil {
+		return 0, err
+	}
+	return parseAdminPort(strings.TrimSpace(string(data)))
+}
+
 func parseAdminPort(addr string) (int, error) {
 	_, portStr, err := net.SplitHostPort(addr)
 	if err != nil {
@@ -0,0 +1,88 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// EnvoyProcess is a live process whose command line carries the func-e
+// run ID marker, regardless of which func-e process started it.
+type EnvoyProcess struct {
+	// Pid is the process ID of Envoy.
+	Pid int
+	// RunID is the value of the func-e appended --run-id flag.
+	RunID string
+	// EnvoyPath is the executable of the process, or the first command line
+	// argument when the executable can't be read.
+	EnvoyPath string
+	// AdminAddressPath is the --admin-address-path flag value, or empty if
+	// missing.
+	AdminAddressPath string
+}
+
+// FindEnvoyProcesses scans all processes visible to the current user for
+// ones tagged with a func-e run ID.
+func FindEnvoyProcesses(ctx context.Context) ([]EnvoyProcess, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var result []EnvoyProcess
+	for _, p := range procs {
+		cmdline, err := p.CmdlineSliceWithContext(ctx)
+		if err != nil || len(cmdline) == 0 {
+			continue // exited, or not ours to inspect
+		}
+		runID, err := extractRunID(cmdline)
+		if err != nil {
+			continue
+		}
+		ep := EnvoyProcess{Pid: int(p.Pid), RunID: runID, EnvoyPath: cmdline[0]}
+		if exe, err := p.ExeWithContext(ctx); err == nil && exe != "" {
+			ep.EnvoyPath = exe
+		}
+		ep.AdminAddressPath, _ = extractAdminAddressPath(cmdline) //nolint:errcheck // optional
+		result = append(result, ep)
+	}
+	return result, nil
+}
+
+// FindEnvoyProcess returns the live Envoy process tagged with runID. When runID
+// is empty, there must be only one tagged Envoy process.
+func FindEnvoyProcess(ctx context.Context, runID string) (*EnvoyProcess, error) {
+	procs, err := FindEnvoyProcesses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return selectOnlyEnvoyProcess(procs, runID)
+}
+
+func selectOnlyEnvoyProcess(procs []EnvoyProcess, runID string) (*EnvoyProcess, error) {
+	var found *EnvoyProcess
+	for i := range procs {
+		if runID != "" && procs[i].RunID != runID {
+			continue
+		}
+		if found != nil {
+			if runID == "" {
+				return nil, errMultipleEnvoyProcesses
+			}
+			return nil, fmt.Errorf("multiple Envoy processes found with %s %s", runIDFlag, runID)
+		}
+		found = &procs[i]
+	}
+	if found != nil {
+		return found, nil
+	}
+	if runID == "" {
+		return nil, errors.New("no Envoy process found")
+	}
+	return nil, fmt.Errorf("no Envoy process found with %s %s", runIDFlag, runID)
+}
@@ -0,0 +1,82 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindEnvoyProcesses(t *testing.T) {
+	adminAddressPath := path.Join(t.TempDir(), "admin.txt")
+	runID := fmt.Sprintf("find-%d", time.Now().UnixNano())
+
+	tagged := exec.CommandContext(t.Context(), "sh", "-c",
+		fmt.Sprintf("sleep 30 && echo %s %s -- --run-id %s", AddressPathFlag, adminAddressPath, runID))
+	untagged := exec.CommandContext(t.Context(), "sh", "-c", "sleep 30 && echo "+runID)
+	require.NoError(t, tagged.Start())
+	require.NoError(t, untagged.Start())
+	t.Cleanup(func() {
+		tagged.Process.Kill()
+		tagged.Process.Wait()
+		untagged.Process.Kill()
+		untagged.Process.Wait()
+	})
+
+	time.Sleep(100 * time.Millisecond)
+
+	procs, err := FindEnvoyProcesses(t.Context())
+	require.NoError(t, err)
+
+	var found []EnvoyProcess
+	for _, p := range procs {
+		if p.RunID == runID {
+			found = append(found, p)
+		}
+	}
+	require.Len(t, found, 1)
+	require.Equal(t, tagged.Process.Pid, found[0].Pid)
+	require.Equal(t, adminAddressPath, found[0].AdminAddressPath)
+	require.NotEmpty(t, found[0].EnvoyPath)
+}
+
+func TestSelectOnlyEnvoyProcess(t *testing.T) {
+	procs := []EnvoyProcess{
+		{Pid: 1, RunID: "run-1"},
+		{Pid: 2, RunID: "run-2"},
+		{Pid: 3, RunID: "run-2"},
+	}
+
+	tests := []struct {
+		name        string
+		procs       []EnvoyProcess
+		runID       string
+		expectedPid int
+		expectedErr string
+	}{
+		{name: "selects by run id", procs: procs, runID: "run-1", expectedPid: 1},
+		{name: "selects the only process", procs: procs[:1], expectedPid: 1},
+		{name: "requires run id when multiple", procs: procs, expectedErr: "multiple Envoy processes found; set --run-id to disambiguate"},
+		{name: "duplicate run id", procs: procs, runID: "run-2", expectedErr: "multiple Envoy processes found with --run-id run-2"},
+		{name: "no processes", expectedErr: "no Envoy process found"},
+		{name: "unknown run id", procs: procs, runID: "run-3", expectedErr: "no Envoy process found with --run-id run-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := selectOnlyEnvoyProcess(tt.procs, tt.runID)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedPid, actual.Pid)
+		})
+	}
+}
@@ -22,6 +22,8 @@ type RunOpts struct {
 	RunID            string // Optional: custom run identifier for StateDir and RuntimeDir paths
 	EnvoyVersion     string
 	EnvoyVersionsURL string
+	TrustedKeys      []string // minisign public keys trusted to sign the Envoy versions JSON
+	RequireSignature bool     // refuse an Envoy versions JSON without a valid signature
 	Out              io.Writer
 	EnvoyOut         io.Writer
 	EnvoyErr         io.Writer
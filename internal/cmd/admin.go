@@ -0,0 +1,124 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/admin"
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+// adminAddressTimeout is how long to wait for a running Envoy to write its admin address.
+const adminAddressTimeout = 5 * time.Second
+
+// NewAdminCmd returns a command that sends a request to the admin API of a running Envoy.
+func NewAdminCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:      "admin",
+		Usage:     "Sends a request to the admin API of a running Envoy",
+		ArgsUsage: "[path]",
+		HideHelp:  true,
+		Description: `The '[path]' is an Envoy admin endpoint, such as "/stats?filter=http",
+"/clusters" or "/config_dump". The response body is printed as-is.
+
+The Envoy is the one started by "run" with the same global --run-id.
+Without it, this queries the only running Envoy started by func-e.
+
+Endpoints that change Envoy's state need -X POST.
+
+Example:
+$ func-e admin /clusters
+$ func-e --run-id 20251012_143053_700 admin -X POST '/logging?level=debug'`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "request",
+				Aliases: []string{"X"},
+				Usage:   "HTTP method of the admin request",
+				Value:   http.MethodGet,
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			path := c.Args().First()
+			if path == "" {
+				return ctx, NewValidationError("missing [path] argument")
+			}
+			if !strings.HasPrefix(path, "/") {
+				return ctx, NewValidationError(fmt.Sprintf("invalid [path] argument: %q should start with %q", path, "/"))
+			}
+			if c.Root().IsSet("run-id") {
+				if err := validateRunIDArg(o.RunID); err != nil { // same rules as [runID] of "logs"
+					return ctx, NewValidationError(fmt.Sprintf("invalid --run-id: %q", o.RunID))
+				}
+			}
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			var runID string
+			if c.Root().IsSet("run-id") { // otherwise, o.RunID is generated
+				runID = o.RunID
+			}
+			adminAddressPath, err := findAdminAddressPath(ctx, o, runID)
+			if err != nil {
+				return err
+			}
+
+			pollCtx, cancel := context.WithTimeout(ctx, adminAddressTimeout)
+			defer cancel()
+			client, err := admin.NewAdminClient(pollCtx, o.HTTPClient, adminAddressPath)
+			if err != nil {
+				return err
+			}
+
+			url := fmt.Sprintf("http://127.0.0.1:%d%s", client.Port(), c.Args().First())
+			req, err := http.NewRequestWithContext(ctx, strings.ToUpper(c.String("request")), url, http.NoBody)
+			if err != nil {
+				return err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close() //nolint:errcheck
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return err
+			}
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				return fmt.Errorf("error Envoy admin URL %s: status_code=%d,body:%s", url, resp.StatusCode, body)
+			}
+			_, err = c.Root().Writer.Write(body)
+			return err
+		},
+	}
+}
+
+// findAdminAddressPath returns the admin address file of the running Envoy
+// with runID, or the only running Envoy when runID is empty.
+func findAdminAddressPath(ctx context.Context, o *globals.GlobalOpts, runID string) (string, error) {
+	if runID != "" {
+		// "run" writes the admin address in the runtime directory, unless the
+		// user passed their own --admin-address-path.
+		if path := filepath.Join(o.EnvoyRuntimeDir(runID), "admin-address.txt"); fileExists(path) {
+			return path, nil
+		}
+	}
+	p, err := admin.FindEnvoyProcess(ctx, runID)
+	if err != nil {
+		return "", err
+	}
+	if p.AdminAddressPath == "" {
+		return "", fmt.Errorf("no %s in the command line of Envoy process %d", admin.AddressPathFlag, p.Pid)
+	}
+	return p.AdminAddressPath, nil
+}
@@ -0,0 +1,130 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+const testAdminConfigYaml = "admin: {address: {socket_address: {address: '127.0.0.1', port_value: 0}}}"
+
+func TestFuncEAdmin(t *testing.T) {
+	o := setupTest(t)
+	runID, _ := startAdminEnvoy(t, o, fakeEnvoyBin, o.EnvoyRuntimeDir)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name:     "GET",
+			args:     []string{"func-e", "--run-id", runID, "admin", "/ready"},
+			expected: "LIVE\n",
+		},
+		{
+			name:     "POST",
+			args:     []string{"func-e", "--run-id", runID, "admin", "-X", "POST", "/logging?level=debug"},
+			expected: "active loggers:\n  main: debug\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			require.NoError(t, c.Run(t.Context(), tc.args))
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncEAdmin_FindsProcess(t *testing.T) {
+	o := setupTest(t)
+	// The admin address isn't in the runtime directory, so it is found via the process command line.
+	adminDir := t.TempDir()
+	runID, _ := startAdminEnvoy(t, o, fakeEnvoyBin, func(string) string { return adminDir })
+
+	c, stdout, _ := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "--run-id", runID, "admin", "/ready"}))
+	require.Equal(t, "LIVE\n", stdout.String())
+}
+
+func TestFuncEAdmin_ErrorStatus(t *testing.T) {
+	o := setupTest(t)
+	runID, _ := startAdminEnvoy(t, o, fakeEnvoyBin, o.EnvoyRuntimeDir)
+
+	c, stdout, _ := newApp(o)
+	err := c.Run(t.Context(), []string{"func-e", "--run-id", runID, "admin", "/logging"})
+	require.ErrorContains(t, err, "status_code=405,body:Method GET not allowed, POST required.")
+	require.Empty(t, stdout)
+}
+
+func TestFuncEAdmin_Validates(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing path",
+			args:        []string{"func-e", "admin"},
+			expectedErr: "missing [path] argument",
+		},
+		{
+			name:        "invalid path",
+			args:        []string{"func-e", "admin", "stats"},
+			expectedErr: `invalid [path] argument: "stats" should start with "/"`,
+		},
+		{
+			name:        "invalid run ID",
+			args:        []string{"func-e", "--run-id", "..", "admin", "/stats"},
+			expectedErr: `invalid --run-id: ".."`,
+		},
+		{
+			name:        "run ID with path separator",
+			args:        []string{"func-e", "--run-id", "../x", "admin", "/stats"},
+			expectedErr: `runID cannot contain path separators (/ or \): "../x"`,
+		},
+		{
+			name:        "unknown run ID",
+			args:        []string{"func-e", "--run-id", "unknown", "admin", "/stats"},
+			expectedErr: "no Envoy process found with --run-id unknown",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			require.EqualError(t, c.Run(t.Context(), tc.args), tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+// startAdminEnvoy runs the fake Envoy at envoyPath with an admin server, tagged with a new run ID as func-e
+// does. adminDir returns where the admin address is written. The returned channel is closed when it exits.
+func startAdminEnvoy(t *testing.T, o *globals.GlobalOpts, envoyPath string, adminDir func(runID string) string) (string, <-chan struct{}) {
+	t.Helper()
+	o.HTTPClient = http.DefaultClient // talk to the real admin server
+
+	runID := fmt.Sprintf("admin-%d", time.Now().UnixNano())
+	require.NoError(t, os.MkdirAll(adminDir(runID), 0o700))
+	adminAddressPath := filepath.Join(adminDir(runID), "admin-address.txt")
+	exited := startFakeEnvoy(t, envoyPath, "--config-yaml", testAdminConfigYaml,
+		"--admin-address-path", adminAddressPath, "--", "--run-id", runID)
+	return runID, exited
+}
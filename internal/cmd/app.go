@@ -23,6 +23,7 @@ func NewApp(o *globals.GlobalOpts) *cli.Command {
 	}
 
 	var envoyVersionsURL, envoyPath, homeDir, configHome, dataHome, stateHome, runtimeDir, platform, runID string
+	var tarballCache, offline, requireSignature bool
 	lastKnownEnvoyPath := fmt.Sprintf("`$FUNC_E_DATA_HOME/envoy-versions/%s`", version.LastKnownEnvoy)
 
 	app := &cli.Command{
@@ -35,7 +36,11 @@ downloads and installs the latest version of Envoy for you.
 To list versions of Envoy you can use, execute ` + "`func-e versions -a`" + `. To
 choose one, invoke ` + fmt.Sprintf("`func-e use %s`", version.LastKnownEnvoy) + `. This installs into
 ` + lastKnownEnvoyPath + `, if not already present. You may
-also use minor version, such as ` + fmt.Sprintf("`func-e use %s`", version.LastKnownEnvoyMinor) + `.
+also use minor version, such as ` + fmt.Sprintf("`func-e use %s`", version.LastKnownEnvoyMinor) + `, or a constraint, such as
+` + "`func-e use '>=1.30 <1.32'`, `~1.31` or `^1.30`" + `. These use the latest match.
+` + "`func-e use latest`" + ` follows the newest release, and ` + "`func-e use stable`" + ` the
+newest patch of the minor version before it. func-e warns when Envoy no
+longer supports the version in use.
 
 ` + "`$ENVOY_PATH`" + ` runs a custom Envoy binary, skipping version
 resolution and download. This is useful for validating pre-release
@@ -44,6 +49,9 @@ or feature branch builds.
 You may want to override ` + "`$ENVOY_VERSIONS_URL`" + ` to supply custom builds or
 otherwise control the source of Envoy binaries. When overriding, validate
 your JSON first: ` + globals.DefaultEnvoyVersionsSchemaURL + `
+` + "`$ENVOY_VERSIONS_URL`" + ` may also be a file:// URL, such as on a mounted
+volume. Tarball URLs in the JSON may be relative to it. To check a JSON
+for problems, execute ` + "`func-e versions validate <url|file>`" + `.
 
 Directory structure:
   ` + "`$FUNC_E_CONFIG_HOME`" + ` stores configuration files
@@ -132,8 +140,38 @@ such as glibc. This value must be constant within a ` + "`$FUNC_E_DATA_HOME`" +
 				Local:       true,
 				Sources:     cli.EnvVars("FUNC_E_PLATFORM"),
 			},
+			&cli.BoolFlag{
+				Name:        "tarball-cache",
+				Usage:       "keep downloaded Envoy archives in $FUNC_E_DATA_HOME/envoy-tarballs to reinstall without the network",
+				Destination: &tarballCache,
+				Local:       true,
+				Sources:     cli.EnvVars("FUNC_E_TARBALL_CACHE"),
+			},
+			&cli.BoolFlag{
+				Name:        "offline",
+				Usage:       "don't use the network: only use installed Envoy versions, and cached versions JSON and archives",
+				Destination: &offline,
+				Local:       true,
+				Sources:     cli.EnvVars("FUNC_E_OFFLINE"),
+			},
+			&cli.BoolFlag{
+				Name:        "require-signature",
+				Usage:       "refuse an Envoy versions JSON not signed by a key in $FUNC_E_CONFIG_HOME/trusted-keys",
+				Destination: &requireSignature,
+				Local:       true,
+				Sources:     cli.EnvVars("FUNC_E_REQUIRE_SIGNATURE"),
+			},
 		},
 		Before: func(ctx context.Context, _ *cli.Command) (context.Context, error) {
+			if tarballCache { // not overridden for tests
+				o.TarballCache = true
+			}
+			if offline { // not overridden for tests, and needed to initialize GetEnvoyVersions
+				o.Offline = true
+			}
+			if requireSignature {
+				o.RequireSignature = true
+			}
 			if err := runtime.InitializeGlobalOpts(o, envoyVersionsURL, envoyPath, homeDir, configHome, dataHome, stateHome, runtimeDir, platform, runID); err != nil {
 				return ctx, NewValidationError(err.Error())
 			}
@@ -142,9 +180,21 @@ such as glibc. This value must be constant within a ` + "`$FUNC_E_DATA_HOME`" +
 		Commands: []*cli.Command{
 			helpCommand,
 			NewRunCmd(o),
+			NewValidateCmd(o),
+			NewLintCmd(o),
 			NewVersionsCmd(o),
 			NewUseCmd(o),
+			NewUpgradeCmd(o),
+			NewInstallCmd(o),
 			NewWhichCmd(o),
+			NewUninstallCmd(o),
+			NewPruneCmd(o),
+			NewMirrorCmd(o),
+			NewRunsCmd(o),
+			NewLogsCmd(o),
+			NewAdminCmd(o),
+			NewPsCmd(o),
+			NewStopCmd(o),
 		},
 	}
 	return app
@@ -297,6 +297,132 @@ func TestPlatformArg(t *testing.T) {
 	}
 }
 
+func TestTarballCache(t *testing.T) {
+	type testCase struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected bool
+	}
+
+	tests := []testCase{
+		{
+			name: "default",
+			args: []string{"func-e"},
+		},
+		{
+			name: "FUNC_E_TARBALL_CACHE env",
+			args: []string{"func-e"},
+			env: map[string]string{
+				"FUNC_E_TARBALL_CACHE": "true",
+			},
+			expected: true,
+		},
+		{
+			name:     "--tarball-cache flag",
+			args:     []string{"func-e", "--tarball-cache"},
+			expected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			o := &globals.GlobalOpts{}
+			err := runTestCommand(t, o, tc.args)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, o.TarballCache)
+		})
+	}
+}
+
+func TestOffline(t *testing.T) {
+	type testCase struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected bool
+	}
+
+	tests := []testCase{
+		{
+			name: "default",
+			args: []string{"func-e"},
+		},
+		{
+			name: "FUNC_E_OFFLINE env",
+			args: []string{"func-e"},
+			env: map[string]string{
+				"FUNC_E_OFFLINE": "1",
+			},
+			expected: true,
+		},
+		{
+			name:     "--offline flag",
+			args:     []string{"func-e", "--offline"},
+			expected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			o := &globals.GlobalOpts{}
+			err := runTestCommand(t, o, tc.args)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, o.Offline)
+		})
+	}
+}
+
+func TestRequireSignature(t *testing.T) {
+	type testCase struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected bool
+	}
+
+	tests := []testCase{
+		{
+			name: "default",
+			args: []string{"func-e"},
+		},
+		{
+			name: "FUNC_E_REQUIRE_SIGNATURE env",
+			args: []string{"func-e"},
+			env: map[string]string{
+				"FUNC_E_REQUIRE_SIGNATURE": "1",
+			},
+			expected: true,
+		},
+		{
+			name:     "--require-signature flag",
+			args:     []string{"func-e", "--require-signature"},
+			expected: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			o := &globals.GlobalOpts{}
+			err := runTestCommand(t, o, tc.args)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, o.RequireSignature)
+		})
+	}
+}
+
 func TestEnvoyPath(t *testing.T) {
 	testDirConfig(t, dirConfigTest{
 		envVar:   "ENVOY_PATH",
@@ -17,7 +17,7 @@ import (
 )
 
 func TestFuncEHelp(t *testing.T) {
-	for _, command := range []string{"", "use", "versions", "run", "which"} {
+	for _, command := range []string{"", "use", "upgrade", "versions", "run", "which", "uninstall", "prune", "runs", "logs", "admin", "ps", "stop", "validate", "lint", "install", "mirror"} {
 		t.Run(command, func(t *testing.T) {
 			c, stdout, _ := newApp(&globals.GlobalOpts{Version: "1.0"})
 			args := []string{"func-e"}
@@ -0,0 +1,168 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+var (
+	// archiveVersionPattern matches the version in an archive name, such as "envoy-v1.31.2-linux-amd64.tar.xz" or
+	// "envoy-v1.31.2_debug-linux-amd64.tar.xz".
+	archiveVersionPattern = regexp.MustCompile(`^envoy-v?(\d+\.\d+\.\d+(?:_debug)?)[-_.]`)
+	// imageVersionPattern matches the version in an image tag, such as "envoyproxy/envoy:v1.31.2".
+	imageVersionPattern = regexp.MustCompile(`:v?(\d+\.\d+\.\d+)(?:@|$)`)
+	sha256SumPattern    = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// NewInstallCmd returns a command that installs Envoy from an archive already on this host, or from an OCI image.
+func NewInstallCmd(o *globals.GlobalOpts) *cli.Command {
+	versionsDir := "$FUNC_E_DATA_HOME/envoy-versions/"
+
+	var v version.PatchVersion
+	var archivePath string
+	var image version.Image
+	var sha256Sum version.SHA256Sum
+	return &cli.Command{
+		Name:      "install",
+		Usage:     "Installs Envoy from a local archive or an OCI image",
+		ArgsUsage: "[version]",
+		HideHelp:  true,
+		Description: `Installs the Envoy archive given by --from-file into ` + versionsDir + `[version].
+This is useful on hosts without network access to the Envoy archives.
+
+The [version] defaults to the one in the archive name. The archive must
+have the name and SHA-256 sum listed for [version] and this platform in
+the Envoy versions JSON, unless --sha256 is given. In that case, the
+versions JSON isn't read.
+
+Instead of an archive, --envoy-image pulls Envoy from an OCI image, at
+/usr/local/bin/envoy like the official ones. The [version] defaults to
+the image tag. The manifest for this platform is selected from the image
+index, and each layer is verified against its digest.
+
+Afterwards, "use" and "run" with the same [version] don't download it.
+
+Example:
+$ func-e install --from-file envoy-v` + version.LastKnownEnvoy.String() + `-linux-amd64.tar.xz
+$ func-e install --envoy-image envoyproxy/envoy:v` + version.LastKnownEnvoy.String(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "from-file",
+				Usage:       "Path to the Envoy archive (tar.xz or tar.gz) to install",
+				Destination: &archivePath,
+			},
+			&cli.StringFlag{
+				Name:  "envoy-image",
+				Usage: "OCI image to pull Envoy from, such as envoyproxy/envoy:v" + version.LastKnownEnvoy.String(),
+			},
+			&cli.StringFlag{
+				Name:  "sha256",
+				Usage: "Expected SHA-256 sum of the archive, instead of the one in the Envoy versions JSON",
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if s := c.String("envoy-image"); s != "" {
+				if archivePath != "" {
+					return ctx, NewValidationError("--from-file and --envoy-image can't be used together")
+				}
+				if c.String("sha256") != "" {
+					return ctx, NewValidationError("--sha256 can't be used with --envoy-image, as layers are verified by digest")
+				}
+				if image = version.NewImage(s); image == "" {
+					return ctx, NewValidationError(fmt.Sprintf("invalid --envoy-image: %q should look like %q", s, "envoyproxy/envoy:v"+version.LastKnownEnvoy.String()))
+				}
+			} else if archivePath == "" {
+				return ctx, NewValidationError("missing --from-file or --envoy-image")
+			}
+			if s := c.String("sha256"); s != "" {
+				if !sha256SumPattern.MatchString(s) {
+					return ctx, NewValidationError(fmt.Sprintf("invalid --sha256: %q should be 64 lowercase hex characters", s))
+				}
+				sha256Sum = version.SHA256Sum(s)
+			}
+
+			// The version defaults to the one in the image tag or archive name.
+			source, m := "the name of "+archivePath, archiveVersionPattern.FindStringSubmatch(filepath.Base(archivePath))
+			if image != "" {
+				source, m = "the tag of "+string(image), imageVersionPattern.FindStringSubmatch(string(image))
+			}
+			if arg := c.Args().First(); arg != "" {
+				if v = version.NewPatchVersion(arg); v == "" {
+					return ctx, NewValidationError(fmt.Sprintf("invalid [version] argument: %q should look like %q", arg, version.LastKnownEnvoy))
+				}
+			} else if m != nil {
+				v = version.NewPatchVersion(m[1])
+			} else {
+				return ctx, NewValidationError(fmt.Sprintf("missing [version] argument: it isn't in %s", source))
+			}
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, _ *cli.Command) error {
+			if image != "" {
+				if err := o.Mkdirs(); err != nil {
+					return err
+				}
+				mtime := time.Now()
+				if evs, err := o.GetEnvoyVersions(ctx); err == nil { // optional, as an image has its own digests
+					mtime = releaseTime(evs, v, mtime)
+				}
+				_, err := envoy.InstallFromImage(ctx, o, v, image, mtime)
+				return err
+			}
+			if _, err := os.Stat(archivePath); err != nil {
+				return err
+			}
+			// Create base XDG directories before any file operations
+			if err := o.Mkdirs(); err != nil {
+				return err
+			}
+
+			mtime := time.Now()
+			if sha256Sum == "" {
+				evs, err := o.GetEnvoyVersions(ctx)
+				if err != nil {
+					return err
+				}
+				// The sum is only for the archive of this version and platform, so don't install another under its name.
+				r := evs.Versions[v]
+				expected := r.Tarballs[o.Platform]
+				if expected == "" {
+					return fmt.Errorf("couldn't find Envoy %s for platform %s in the Envoy versions JSON. Use --sha256 to install it", v, o.Platform)
+				}
+				tarball := version.Tarball(filepath.Base(archivePath))
+				if tarball != expected.Tarball() {
+					return fmt.Errorf("%q isn't the archive of Envoy %s for platform %s, %q. Use --sha256 to install it", tarball, v, o.Platform, expected.Tarball())
+				}
+				if sha256Sum = evs.SHA256Sums[tarball]; sha256Sum == "" {
+					return fmt.Errorf("couldn't find sha256Sum of %q in the Envoy versions JSON. Use --sha256 to give it", tarball)
+				}
+				mtime = releaseTime(evs, v, mtime)
+			}
+
+			_, err := envoy.InstallFromFile(ctx, o, v, archivePath, sha256Sum, mtime)
+			return err
+		},
+	}
+}
+
+// releaseTime returns the release date of v in evs, so that an install preserves it like a download does, or
+// otherwise the given time.
+func releaseTime(evs *version.ReleaseVersions, v version.PatchVersion, otherwise time.Time) time.Time {
+	if t, err := time.Parse("2006-01-02", string(evs.Versions[v].ReleaseDate)); err == nil {
+		return t
+	}
+	return otherwise
+}
@@ -0,0 +1,235 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/admin"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/test"
+	"github.com/tetratelabs/func-e/internal/test/httptest"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFuncEInstall_Validates(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing --from-file",
+			expectedErr: "missing --from-file or --envoy-image",
+		},
+		{
+			name:        "--from-file and --envoy-image",
+			args:        []string{"--from-file", "envoy.tar.xz", "--envoy-image", "envoyproxy/envoy:v1.31.2"},
+			expectedErr: "--from-file and --envoy-image can't be used together",
+		},
+		{
+			name:        "--sha256 and --envoy-image",
+			args:        []string{"--envoy-image", "envoyproxy/envoy:v1.31.2", "--sha256", strings.Repeat("0", 64)},
+			expectedErr: "--sha256 can't be used with --envoy-image, as layers are verified by digest",
+		},
+		{
+			name:        "invalid --envoy-image",
+			args:        []string{"--envoy-image", "Envoy Proxy"},
+			expectedErr: fmt.Sprintf(`invalid --envoy-image: "Envoy Proxy" should look like "envoyproxy/envoy:v%s"`, version.LastKnownEnvoy),
+		},
+		{
+			name:        "no version in tag",
+			args:        []string{"--envoy-image", "envoyproxy/envoy:distroless-dev"},
+			expectedErr: "missing [version] argument: it isn't in the tag of envoyproxy/envoy:distroless-dev",
+		},
+		{
+			name:        "invalid --sha256",
+			args:        []string{"--from-file", "envoy-v1.31.2-linux-amd64.tar.xz", "--sha256", "ABC"},
+			expectedErr: `invalid --sha256: "ABC" should be 64 lowercase hex characters`,
+		},
+		{
+			name:        "invalid version",
+			args:        []string{"--from-file", "envoy.tar.xz", "a.b.c"},
+			expectedErr: fmt.Sprintf(`invalid [version] argument: "a.b.c" should look like %q`, version.LastKnownEnvoy),
+		},
+		{
+			name:        "no version in name",
+			args:        []string{"--from-file", "envoy.tar.xz"},
+			expectedErr: "missing [version] argument: it isn't in the name of envoy.tar.xz",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			err := c.Run(t.Context(), append([]string{"func-e", "install"}, tc.args...))
+
+			require.EqualError(t, err, tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncEInstall(t *testing.T) {
+	tarballURL := test.TarballURL("http://"+admin.ServerAddr, runtime.GOOS, runtime.GOARCH, version.LastKnownEnvoy)
+	wrongSum := strings.Repeat("0", 64)
+
+	tests := []struct {
+		name string
+		// $ARCHIVE and $SHA256 in args and expectedErr are replaced with the archive path and its SHA-256 sum.
+		args []string
+		// archiveName defaults to "my-envoy.tar.gz". "$LISTED" is replaced with the name in the versions JSON.
+		archiveName     string
+		expectedVersion string // defaults to version.LastKnownEnvoy
+		expectedMtime   time.Time
+		expectedErr     string
+	}{
+		{
+			name:          "version and sha256 from versions JSON",
+			args:          []string{"--from-file", "$ARCHIVE"},
+			archiveName:   "$LISTED",
+			expectedMtime: time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), // test.FakeReleaseDate
+		},
+		{
+			name: "--sha256",
+			args: []string{"--from-file", "$ARCHIVE", "--sha256", "$SHA256", version.LastKnownEnvoy.String()},
+		},
+		{
+			name:            "--sha256 debug version from archive name",
+			args:            []string{"--from-file", "$ARCHIVE", "--sha256", "$SHA256"},
+			archiveName:     "envoy-v" + version.LastKnownEnvoy.String() + "_debug-linux-amd64.tar.gz",
+			expectedVersion: version.LastKnownEnvoy.String() + "_debug",
+		},
+		{
+			name: "not the archive in versions JSON",
+			args: []string{"--from-file", "$ARCHIVE", version.LastKnownEnvoy.String()},
+			expectedErr: fmt.Sprintf(`"my-envoy.tar.gz" isn't the archive of Envoy %s for platform %s, "$LISTED". Use --sha256 to install it`,
+				version.LastKnownEnvoy, globals.DefaultPlatform),
+		},
+		{
+			name:        "version not in versions JSON",
+			args:        []string{"--from-file", "$ARCHIVE", "1.2.3"},
+			archiveName: "$LISTED",
+			expectedErr: fmt.Sprintf(`couldn't find Envoy 1.2.3 for platform %s in the Envoy versions JSON. Use --sha256 to install it`,
+				globals.DefaultPlatform),
+		},
+		{
+			name:        "wrong --sha256",
+			args:        []string{"--from-file", "$ARCHIVE", "--sha256", wrongSum, version.LastKnownEnvoy.String()},
+			expectedErr: `error verifying $ARCHIVE: expected SHA-256 sum "` + wrongSum + `", but have "$SHA256"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTest(t)
+			// Use the archive the versions server has, as each fake Envoy archive has a different SHA-256 sum.
+			res, err := o.HTTPClient.Get(string(tarballURL))
+			require.NoError(t, err)
+			tarball, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			sha256Sum := fmt.Sprintf("%x", sha256.Sum256(tarball))
+
+			listed := path.Base(string(tarballURL))
+			archiveName := "my-envoy.tar.gz"
+			if tc.archiveName != "" {
+				archiveName = strings.ReplaceAll(tc.archiveName, "$LISTED", listed)
+			}
+			archivePath := filepath.Join(t.TempDir(), archiveName)
+			require.NoError(t, os.WriteFile(archivePath, tarball, 0o600))
+			r := strings.NewReplacer("$ARCHIVE", archivePath, "$SHA256", sha256Sum, "$LISTED", listed)
+			args := []string{"func-e", "install"}
+			for _, a := range tc.args {
+				args = append(args, r.Replace(a))
+			}
+
+			c, stdout, _ := newApp(o)
+			err = c.Run(t.Context(), args)
+
+			expectedVersion := version.LastKnownEnvoy.String()
+			if tc.expectedVersion != "" {
+				expectedVersion = tc.expectedVersion
+			}
+			installPath := filepath.Join(o.EnvoyVersionsDir(), expectedVersion)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, r.Replace(tc.expectedErr))
+				require.NoDirExists(t, installPath)
+				return
+			}
+			require.NoError(t, err)
+			require.Contains(t, stdout.String(), "installing "+expectedVersion)
+			require.FileExists(t, filepath.Join(installPath, "bin", "envoy"))
+			if !tc.expectedMtime.IsZero() {
+				stat, err := os.Stat(installPath)
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedMtime, stat.ModTime().UTC())
+			}
+		})
+	}
+}
+
+func TestFuncEInstall_EnvoyImage(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		expectedVersion string
+		expectedMtime   time.Time // zero when not in the versions JSON, so the time of install
+	}{
+		{
+			name:            "version from tag",
+			args:            []string{"--envoy-image", "registry.example.com/envoyproxy/envoy:v1.31.2"},
+			expectedVersion: "1.31.2",
+		},
+		{
+			name:            "version argument",
+			args:            []string{"--envoy-image", "registry.example.com/envoyproxy/envoy:v1.31.2", "1.31.3"},
+			expectedVersion: "1.31.3",
+		},
+		{
+			name:            "release date from versions JSON",
+			args:            []string{"--envoy-image", "registry.example.com/envoyproxy/envoy:v1.31.2", version.LastKnownEnvoy.String()},
+			expectedVersion: version.LastKnownEnvoy.String(),
+			expectedMtime:   time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), // test.FakeReleaseDate
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTest(t)
+			o.Platform = "linux/amd64"
+			o.HTTPClient = httptest.HTTPClient(test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2",
+				map[version.Platform][]byte{"linux/amd64": []byte("envoy")}))
+
+			start := time.Now().Truncate(time.Second)
+			c, stdout, _ := newApp(o)
+			require.NoError(t, c.Run(t.Context(), append([]string{"func-e", "install"}, tc.args...)))
+
+			require.Equal(t, "pulling registry.example.com/envoyproxy/envoy:v1.31.2 for linux/amd64\n", stdout.String())
+			installPath := filepath.Join(o.EnvoyVersionsDir(), tc.expectedVersion)
+			require.FileExists(t, filepath.Join(installPath, "bin", "envoy"))
+			stat, err := os.Stat(installPath)
+			require.NoError(t, err)
+			if tc.expectedMtime.IsZero() {
+				require.False(t, stat.ModTime().Before(start))
+			} else {
+				require.Equal(t, tc.expectedMtime, stat.ModTime().UTC())
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncELint(t *testing.T) {
+	configYaml := `admin: {address: {socket_address: {address: 0.0.0.0, port_value: 0}}}
+static_resources:
+  listeners:
+    - name: main
+      address: {socket_address: {address: 127.0.0.1, port_value: 0}}
+      listener_filters: [{name: envoy.filters.listener.tls_inspector}]`
+
+	tests := []struct {
+		name        string
+		args        []string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name: "no problems",
+			args: []string{"func-e", "lint", "--config-yaml", "static_resources: {}"},
+		},
+		{
+			name: "no problems json",
+			args: []string{"func-e", "lint", "--config-yaml", "static_resources: {}", "-o", "json"},
+			expected: `[]
+`,
+		},
+		{
+			name: "text",
+			args: []string{"func-e", "lint", "--config-yaml", configYaml},
+			expected: `--config-yaml:1:35: admin address 0.0.0.0:0 is reachable from other hosts, but the admin API can change Envoy (admin-not-loopback)
+--config-yaml:6:26: filter of listener "main" has no typed_config "@type" (missing-type)
+`,
+			expectedErr: "found 2 problem(s) in Envoy configuration",
+		},
+		{
+			name: "json",
+			args: []string{"func-e", "lint", "--config-yaml", configYaml, "--output", "json"},
+			expected: `[
+  {
+    "file": "--config-yaml",
+    "line": 1,
+    "column": 35,
+    "check": "admin-not-loopback",
+    "message": "admin address 0.0.0.0:0 is reachable from other hosts, but the admin API can change Envoy"
+  },
+  {
+    "file": "--config-yaml",
+    "line": 6,
+    "column": 26,
+    "check": "missing-type",
+    "message": "filter of listener \"main\" has no typed_config \"@type\""
+  }
+]
+`,
+			expectedErr: "found 2 problem(s) in Envoy configuration",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(setupTest(t))
+			err := c.Run(t.Context(), tc.args)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncELint_Validates(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing config",
+			args:        []string{"func-e", "lint"},
+			expectedErr: "at least one of --config-path or --config-yaml is required",
+		},
+		{
+			name:        "invalid output",
+			args:        []string{"func-e", "lint", "-c", "envoy.yaml", "-o", "xml"},
+			expectedErr: `invalid --output "xml", should be "text" or "json"`,
+		},
+		{
+			name:        "missing file",
+			args:        []string{"func-e", "lint", "-c", "does-not-exist.yaml"},
+			expectedErr: "failed to read config file does-not-exist.yaml: open does-not-exist.yaml: no such file or directory",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			require.EqualError(t, c.Run(t.Context(), tc.args), tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+const (
+	// logsPollInterval is how often --follow checks for new log lines.
+	logsPollInterval = 200 * time.Millisecond
+	// envoyTimestampLayout is the timestamp at the start of Envoy's default log format "[%Y-%m-%d %T.%e]...".
+	envoyTimestampLayout = "2006-01-02 15:04:05.000"
+)
+
+// NewLogsCmd returns a command that prints the stdout and stderr logs of a run.
+func NewLogsCmd(o *globals.GlobalOpts) *cli.Command {
+	var since time.Time
+	return &cli.Command{
+		Name:      "logs",
+		Usage:     `Prints the console output of a "run" command`,
+		ArgsUsage: "[runID]",
+		HideHelp:  true,
+		Description: `The '[runID]' is from the "runs" command, or the global --run-id flag.
+When neither is set, this prints the logs of the most recent run.
+
+Lines from "stdout.log" and "stderr.log" are interleaved by the timestamp
+at the start of Envoy's default log format. Lines without it, such as in a
+custom log format, are printed first and aren't filtered by --since. Use
+--stdout or --stderr to print only one of them.
+
+Example:
+$ func-e logs --stderr --since 10m --follow`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "follow",
+				Aliases: []string{"f"},
+				Usage:   "Keep printing new lines until the run ends",
+			},
+			&cli.BoolFlag{
+				Name:  "stdout",
+				Usage: "Print only stdout.log",
+			},
+			&cli.BoolFlag{
+				Name:  "stderr",
+				Usage: "Print only stderr.log",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: `Print lines logged since a duration ago (ex. "10m") or an RFC3339 time`,
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if runID := c.Args().First(); runID != "" {
+				if err := validateRunIDArg(runID); err != nil {
+					return ctx, err
+				}
+			}
+			if s := c.String("since"); s != "" {
+				var err error
+				if since, err = parseSince(s, time.Now()); err != nil {
+					return ctx, NewValidationError(err.Error())
+				}
+			}
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			runDir, err := logsRunDir(o, c)
+			if err != nil {
+				return err
+			}
+
+			var streams []*logFile
+			// Selecting neither stream is the same as selecting both.
+			if c.Bool("stdout") || !c.Bool("stderr") {
+				streams = append(streams, &logFile{path: filepath.Join(runDir, "stdout.log")})
+			}
+			if c.Bool("stderr") || !c.Bool("stdout") {
+				streams = append(streams, &logFile{path: filepath.Join(runDir, "stderr.log")})
+			}
+
+			l := &logPrinter{out: c.Root().Writer, streams: streams, since: since}
+			if !c.Bool("follow") {
+				return l.print(true)
+			}
+			return l.follow(ctx, runDir)
+		},
+	}
+}
+
+// logsRunDir resolves the run directory from the [runID] argument, the global --run-id flag or the most recent run.
+func logsRunDir(o *globals.GlobalOpts, c *cli.Command) (string, error) {
+	runID := c.Args().First()
+	if runID == "" && c.Root().IsSet("run-id") {
+		runID = o.RunID
+	}
+	if runID == "" {
+		rows, err := getRuns(o.EnvoyRunsDir())
+		if err != nil {
+			return "", err
+		}
+		if len(rows) == 0 {
+			return "", fmt.Errorf("no runs found in %s", o.EnvoyRunsDir())
+		}
+		return rows[0].runDir, nil
+	}
+
+	runDir := o.EnvoyRunDir(runID)
+	if _, err := os.Stat(runDir); os.IsNotExist(err) {
+		return "", fmt.Errorf("run %s not found in %s", runID, o.EnvoyRunsDir())
+	} else if err != nil {
+		return "", err
+	}
+	return runDir, nil
+}
+
+// parseSince parses a duration before now, or an RFC3339 time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since: %q should look like %q or %q", s, "10m", "2006-01-02T15:04:05Z")
+}
+
+// logLine is a line of a log file with the timestamp of the Envoy log entry it belongs to.
+type logLine struct {
+	ts   time.Time
+	text string
+}
+
+// logFile reads lines appended to a log file since the last read.
+type logFile struct {
+	path    string
+	offset  int64
+	partial []byte
+	// lastTS is the timestamp of the last Envoy log entry, which applies to continuation lines.
+	lastTS time.Time
+}
+
+// readLines returns the complete lines appended since the last call. When final, a trailing line without a
+// newline is also returned.
+func (f *logFile) readLines(final bool) ([]logLine, error) {
+	file, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil // Envoy hasn't written this stream, yet
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close() //nolint:errcheck // read-only
+
+	if _, err = file.Seek(f.offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	f.offset += int64(len(data))
+	data = append(f.partial, data...)
+
+	end := bytes.LastIndexByte(data, '\n') + 1
+	if final {
+		end = len(data)
+	}
+	f.partial = append([]byte(nil), data[end:]...)
+	if end == 0 {
+		return nil, nil
+	}
+
+	var lines []logLine
+	for _, text := range strings.Split(strings.TrimSuffix(string(data[:end]), "\n"), "\n") {
+		if ts, ok := parseEnvoyTimestamp(text); ok {
+			f.lastTS = ts
+		}
+		lines = append(lines, logLine{f.lastTS, text})
+	}
+	return lines, nil
+}
+
+// parseEnvoyTimestamp parses the timestamp of Envoy's default log format, ex. "[2025-10-12 14:30:53.123][1][info]...".
+func parseEnvoyTimestamp(line string) (time.Time, bool) {
+	if len(line) < len(envoyTimestampLayout)+2 || line[0] != '[' || line[len(envoyTimestampLayout)+1] != ']' {
+		return time.Time{}, false
+	}
+	ts, err := time.ParseInLocation(envoyTimestampLayout, line[1:len(envoyTimestampLayout)+1], time.Local)
+	return ts, err == nil
+}
+
+// mergeLines interleaves lines by timestamp, preserving the order of each input on tie. Lines without a timestamp,
+// such as before the first Envoy log entry or in a custom log format, can't be placed, so are taken first.
+func mergeLines(a, b []logLine) []logLine {
+	merged := make([]logLine, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if b[0].ts.Before(a[0].ts) {
+			merged, b = append(merged, b[0]), b[1:]
+		} else {
+			merged, a = append(merged, a[0]), a[1:]
+		}
+	}
+	return append(append(merged, a...), b...)
+}
+
+type logPrinter struct {
+	out     io.Writer
+	streams []*logFile
+	since   time.Time
+}
+
+// print writes lines appended to the streams since the last call.
+func (l *logPrinter) print(final bool) error {
+	var lines []logLine
+	for _, s := range l.streams {
+		sl, err := s.readLines(final)
+		if err != nil {
+			return err
+		}
+		lines = mergeLines(lines, sl)
+	}
+	for _, line := range lines {
+		// Lines without a timestamp can't be filtered, so are printed.
+		if !l.since.IsZero() && !line.ts.IsZero() && line.ts.Before(l.since) {
+			continue
+		}
+		if _, err := fmt.Fprintln(l.out, line.text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow prints lines as they are written until the run in runDir ends or the context is done.
+func (l *logPrinter) follow(ctx context.Context, runDir string) error {
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		// Check before reading, so that lines written just before the end are printed.
+		ended := false
+		if info, err := envoy.ReadRunInfo(runDir); err == nil {
+			ended = !runActive(ctx, info)
+		}
+		if err := l.print(ended); err != nil || ended {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+const (
+	testStdoutLog = `[2025-10-12 14:30:53.100][1][info][main] out 1
+[2025-10-12 14:30:53.300][1][info][main] out 3
+`
+	testStderrLog = `initializing epoch 0
+[2025-10-12 14:30:53.200][1][warning][main] err 2
+  err 2 continued
+[2025-10-12 14:30:53.400][1][info][main] err 4
+`
+)
+
+func TestFuncELogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "latest run interleaved",
+			args: []string{"func-e", "logs"},
+			expected: `initializing epoch 0
+[2025-10-12 14:30:53.100][1][info][main] out 1
+[2025-10-12 14:30:53.200][1][warning][main] err 2
+  err 2 continued
+[2025-10-12 14:30:53.300][1][info][main] out 3
+[2025-10-12 14:30:53.400][1][info][main] err 4
+`,
+		},
+		{
+			name:     "stdout",
+			args:     []string{"func-e", "logs", "--stdout"},
+			expected: testStdoutLog,
+		},
+		{
+			name:     "stderr",
+			args:     []string{"func-e", "logs", "--stderr"},
+			expected: testStderrLog,
+		},
+		{
+			name: "since",
+			args: []string{"func-e", "logs", "--since", time.Date(2025, 10, 12, 14, 30, 53, 300000000, time.Local).Format(time.RFC3339Nano)},
+			expected: `initializing epoch 0
+[2025-10-12 14:30:53.300][1][info][main] out 3
+[2025-10-12 14:30:53.400][1][info][main] err 4
+`,
+		},
+		{
+			name:     "run ID argument",
+			args:     []string{"func-e", "logs", "20251012_143053_700"},
+			expected: "older\n",
+		},
+		{
+			name:     "global run ID flag",
+			args:     []string{"func-e", "--run-id", "20251012_143053_700", "logs"},
+			expected: "older\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTestLogs(t)
+			c, stdout, stderr := newApp(o)
+			require.NoError(t, c.Run(t.Context(), tc.args))
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncELogs_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		setup       func(t *testing.T) *globals.GlobalOpts
+		expectedErr string
+	}{
+		{
+			name:        "no runs",
+			args:        []string{"func-e", "logs"},
+			setup:       setupTest,
+			expectedErr: "no runs found in $RUNS_DIR",
+		},
+		{
+			name:        "unknown run ID",
+			args:        []string{"func-e", "logs", "unknown"},
+			setup:       setupTestLogs,
+			expectedErr: "run unknown not found in $RUNS_DIR",
+		},
+		{
+			name:        "invalid run ID",
+			args:        []string{"func-e", "logs", ".."},
+			setup:       setupTestLogs,
+			expectedErr: `invalid [runID] argument: ".."`,
+		},
+		{
+			name:        "invalid since",
+			args:        []string{"func-e", "logs", "--since", "yesterday"},
+			setup:       setupTestLogs,
+			expectedErr: `invalid --since: "yesterday" should look like "10m" or "2006-01-02T15:04:05Z"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := tc.setup(t)
+			c, stdout, stderr := newApp(o)
+			err := c.Run(t.Context(), tc.args)
+			require.EqualError(t, err, replaceRunsDir(tc.expectedErr, o))
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+// TestFuncELogs_SinceCustomFormat ensures lines of a custom log format, which have no timestamp func-e can parse, are
+// printed instead of filtered by --since.
+func TestFuncELogs_SinceCustomFormat(t *testing.T) {
+	o := setupTest(t)
+	writeTestRun(t, o, "20251012_150000_000", testRunInfo("20251012_150000_000"))
+	runDir := o.EnvoyRunDir("20251012_150000_000")
+	customLog := "2025-10-12T14:30:53.100Z info main out 1\n2025-10-12T14:30:53.300Z info main out 3\n"
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "stdout.log"), []byte(customLog), 0o600))
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "logs", "--since", "10m"}))
+	require.Equal(t, customLog, stdout.String())
+	require.Empty(t, stderr)
+}
+
+func TestFuncELogs_Follow(t *testing.T) {
+	o := setupTest(t)
+	info := testRunInfo("20251012_150000_000")
+	info.EndTime, info.ExitStatus = nil, nil
+	info.Pid = os.Getpid() // a live process
+	writeTestRun(t, o, "20251012_150000_000", info)
+	runDir := o.EnvoyRunDir("20251012_150000_000")
+	stderrLog := filepath.Join(runDir, "stderr.log")
+
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		_ = os.WriteFile(stderrLog, []byte("first\nsec"), 0o600)
+		time.Sleep(300 * time.Millisecond)
+		_ = os.WriteFile(stderrLog, []byte("first\nsecond\nthird"), 0o600)
+		end := time.Now()
+		exitStatus := 0
+		info.EndTime, info.ExitStatus = &end, &exitStatus
+		_ = envoy.WriteRunInfo(runDir, info)
+	}()
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "logs", "-f"}))
+	require.Equal(t, "first\nsecond\nthird\n", stdout.String())
+	require.Empty(t, stderr)
+}
+
+// setupTestLogs creates an older and a newer run with logs in Envoy's default format.
+func setupTestLogs(t *testing.T) *globals.GlobalOpts {
+	t.Helper()
+	o := setupTest(t)
+
+	writeTestRun(t, o, "20251012_143053_700", testRunInfo("20251012_143053_700"))
+	require.NoError(t, os.WriteFile(filepath.Join(o.EnvoyRunDir("20251012_143053_700"), "stderr.log"), []byte("older\n"), 0o600))
+
+	latest := testRunInfo("20251012_150000_000")
+	latest.StartTime = latest.StartTime.Add(time.Hour)
+	writeTestRun(t, o, "20251012_150000_000", latest)
+	runDir := o.EnvoyRunDir("20251012_150000_000")
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "stdout.log"), []byte(testStdoutLog), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "stderr.log"), []byte(testStderrLog), 0o600))
+	return o
+}
+
+func replaceRunsDir(s string, o *globals.GlobalOpts) string {
+	return strings.ReplaceAll(s, "$RUNS_DIR", o.EnvoyRunsDir())
+}
@@ -0,0 +1,99 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvoyTimestamp(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected time.Time
+		ok       bool
+	}{
+		{
+			name:     "default log format",
+			line:     "[2025-10-12 14:30:53.123][1][info][main] starting main dispatch loop",
+			expected: time.Date(2025, 10, 12, 14, 30, 53, 123000000, time.Local),
+			ok:       true,
+		},
+		{name: "continuation line", line: "  stack trace line"},
+		{name: "custom log format", line: "[info] starting main dispatch loop"},
+		{name: "empty", line: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := parseEnvoyTimestamp(tc.line)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 10, 12, 14, 30, 0, 0, time.UTC)
+
+	actual, err := parseSince("10m", now)
+	require.NoError(t, err)
+	require.Equal(t, now.Add(-10*time.Minute), actual)
+
+	actual, err = parseSince("2025-10-12T14:00:00Z", now)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2025, 10, 12, 14, 0, 0, 0, time.UTC), actual)
+
+	_, err = parseSince("yesterday", now)
+	require.EqualError(t, err, `invalid --since: "yesterday" should look like "10m" or "2006-01-02T15:04:05Z"`)
+}
+
+func TestMergeLines(t *testing.T) {
+	t1 := time.Date(2025, 10, 12, 14, 30, 1, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	t3 := t2.Add(time.Second)
+
+	stdout := []logLine{{t1, "out 1"}, {t3, "out 3"}}
+	stderr := []logLine{{time.Time{}, "no timestamp"}, {t1, "err 1"}, {t2, "err 2"}, {t2, "err 2 continued"}}
+
+	require.Equal(t, []logLine{
+		{time.Time{}, "no timestamp"},
+		{t1, "out 1"},
+		{t1, "err 1"},
+		{t2, "err 2"},
+		{t2, "err 2 continued"},
+		{t3, "out 3"},
+	}, mergeLines(stdout, stderr))
+}
+
+func TestLogFile_ReadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stderr.log")
+	f := &logFile{path: path}
+
+	lines, err := f.readLines(false)
+	require.NoError(t, err, "missing file is not yet written")
+	require.Empty(t, lines)
+
+	ts := time.Date(2025, 10, 12, 14, 30, 53, 0, time.Local)
+	require.NoError(t, os.WriteFile(path, []byte("[2025-10-12 14:30:53.000][1][info] a\ncontinued\npart"), 0o600))
+	lines, err = f.readLines(false)
+	require.NoError(t, err)
+	require.Equal(t, []logLine{{ts, "[2025-10-12 14:30:53.000][1][info] a"}, {ts, "continued"}}, lines)
+
+	// The partial line is only returned once complete, or on the final read.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString("ial\nlast")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	lines, err = f.readLines(true)
+	require.NoError(t, err)
+	require.Equal(t, []logLine{{ts, "partial"}, {ts, "last"}}, lines)
+}
@@ -0,0 +1,205 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// NewMirrorCmd returns a command that helps maintain a private mirror of Envoy archives.
+func NewMirrorCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:     "mirror",
+		Usage:    "Maintains a private mirror of Envoy archives",
+		HideHelp: true,
+		Description: `Commands for the maintainer of a mirror, set with ` + "`$ENVOY_VERSIONS_URL`" + `.
+
+Example:
+$ func-e mirror generate --base-url https://example.com/envoy ./envoy
+$ func-e mirror serve --listen :8080`,
+		Commands: []*cli.Command{newMirrorGenerateCmd(o), newMirrorServeCmd(o)},
+	}
+}
+
+// newMirrorGenerateCmd returns a command that writes the Envoy versions JSON for a directory of Envoy archives.
+func newMirrorGenerateCmd(_ *globals.GlobalOpts) *cli.Command {
+	var dir, baseURL, devCommitSha, merge, output string
+	var releaseDate version.ReleaseDate
+	return &cli.Command{
+		Name:      "generate",
+		Usage:     "Generates the Envoy versions JSON for a directory of Envoy archives",
+		ArgsUsage: "<dir>",
+		HideHelp:  true,
+		Description: `Scans <dir> for archives named like envoy-v` + version.LastKnownEnvoy.String() + `-linux-amd64.tar.xz,
+and writes the Envoy versions JSON listing them, with their SHA-256 sums.
+Archives named like envoy-dev-linux-amd64.tar.xz are the "dev" entry.
+
+The release date of a version is the modification time of its oldest
+archive, unless --release-date is given. To keep the versions already in
+a JSON, pass it to --merge.
+
+Example:
+$ func-e mirror generate --base-url https://example.com/envoy \
+    --merge envoy-versions.json -o envoy-versions.json ./envoy`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "base-url",
+				Usage:       "URL that <dir> is served from, such as https://example.com/envoy",
+				Destination: &baseURL,
+			},
+			&cli.StringFlag{
+				Name:  "release-date",
+				Usage: "Release date of all archives (YYYY-MM-DD), instead of their modification times",
+			},
+			&cli.StringFlag{
+				Name:        "dev-commit-sha",
+				Usage:       "Envoy commit of the dev archives, required when there are any",
+				Destination: &devCommitSha,
+			},
+			&cli.StringFlag{
+				Name:        "merge",
+				Usage:       "Path to an Envoy versions JSON whose versions are kept",
+				Destination: &merge,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "Path to write the Envoy versions JSON to, instead of stdout",
+				Destination: &output,
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if dir = c.Args().First(); dir == "" {
+				return ctx, NewValidationError("missing <dir> argument")
+			}
+			if baseURL == "" {
+				return ctx, NewValidationError("missing --base-url")
+			}
+			if u, err := url.Parse(baseURL); err != nil || u.Scheme == "" || u.Host == "" && u.Scheme != "file" {
+				return ctx, NewValidationError(fmt.Sprintf("invalid --base-url: %q should look like %q", baseURL, "https://example.com/envoy"))
+			}
+			if d := c.String("release-date"); d != "" {
+				if _, err := time.Parse("2006-01-02", d); err != nil {
+					return ctx, NewValidationError(fmt.Sprintf("invalid --release-date: %q should look like %q", d, "2024-01-31"))
+				}
+				releaseDate = version.ReleaseDate(d)
+			}
+			if devCommitSha != "" && len(devCommitSha) < 8 {
+				return ctx, NewValidationError(fmt.Sprintf("invalid --dev-commit-sha: %q should have at least 8 characters", devCommitSha))
+			}
+			return ctx, nil
+		},
+		Action: func(_ context.Context, c *cli.Command) error {
+			evs, err := envoy.GenerateVersions(dir, baseURL, releaseDate, devCommitSha)
+			if err != nil {
+				return err
+			}
+			if merge != "" {
+				b, err := os.ReadFile(merge) //nolint:gosec // merge is given by the user
+				if err != nil {
+					return err
+				}
+				var existing version.ReleaseVersions
+				if err = json.Unmarshal(b, &existing); err != nil {
+					return fmt.Errorf("error unmarshalling %s: %w", merge, err)
+				}
+				envoy.MergeVersions(&existing, evs)
+				evs = &existing
+			}
+			if err = evs.Validate(); err != nil {
+				return fmt.Errorf("invalid Envoy versions JSON:\n%w", err)
+			}
+
+			b, err := json.MarshalIndent(evs, "", "  ")
+			if err != nil {
+				return err
+			}
+			b = append(b, '\n')
+			if output == "" {
+				_, err = c.Root().Writer.Write(b)
+				return err
+			}
+			return os.WriteFile(output, b, 0o644) //nolint:gosec // the Envoy versions JSON is public
+		},
+	}
+}
+
+// newMirrorServeCmd returns a command that serves the installed and cached Envoy versions to other func-e.
+func newMirrorServeCmd(o *globals.GlobalOpts) *cli.Command {
+	var listen string
+	return &cli.Command{
+		Name:     "serve",
+		Usage:    "Serves the installed Envoy versions to other func-e over HTTP",
+		HideHelp: true,
+		Description: `Serves the Envoy versions installed in $FUNC_E_DATA_HOME/envoy-versions,
+or cached in $FUNC_E_DATA_HOME/envoy-tarballs, until interrupted. Other
+func-e can set ` + "`$ENVOY_VERSIONS_URL`" + ` to its /envoy-versions.json, such as on a
+network without access to the Envoy archives.
+
+Installed versions without a cached archive are archived reproducibly, so
+the SHA-256 sums are the same each time they are served.
+
+Release dates are from the cached Envoy versions JSON. Otherwise, they
+are the modification time of the installed version, or of the archive.
+
+Example:
+$ func-e mirror serve --listen :8080
+$ ENVOY_VERSIONS_URL=http://mirror-host:8080/envoy-versions.json func-e run -c envoy.yaml`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "listen",
+				Usage:       "Address to listen on",
+				Value:       ":8080",
+				Destination: &listen,
+			},
+		},
+		Action: func(ctx context.Context, _ *cli.Command) error {
+			if err := o.Mkdirs(); err != nil {
+				return err
+			}
+			workDir, err := os.MkdirTemp(o.RuntimeDir, "mirror-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(workDir) //nolint:errcheck // best effort, as the runtime directory is temporary
+
+			m, err := envoy.NewMirror(ctx, o, workDir)
+			if err != nil {
+				return err
+			}
+			if len(m.Versions.Versions) == 0 {
+				return fmt.Errorf("no Envoy versions to serve: install them with \"func-e use\" or \"func-e install\"")
+			}
+
+			ln, err := net.Listen("tcp", listen)
+			if err != nil {
+				return err
+			}
+			o.Logf("serving %d Envoy versions at http://%s/envoy-versions.json\n", len(m.Versions.Versions), ln.Addr())
+			srv := &http.Server{Handler: m, ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				<-ctx.Done()
+				_ = srv.Close()
+			}()
+			if err = srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFuncEMirrorGenerate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "envoy-v1.32.0-linux-amd64.tar.xz"), []byte("a"), 0o600))
+	existing := filepath.Join(t.TempDir(), "envoy-versions.json")
+	require.NoError(t, os.WriteFile(existing, []byte(`{"versions":{"1.31.2":{"releaseDate":"2024-09-19",`+
+		`"tarballs":{"linux/amd64":"https://example.com/envoy/envoy-v1.31.2-linux-amd64.tar.xz"}}},`+
+		`"sha256sums":{"envoy-v1.31.2-linux-amd64.tar.xz":"`+strings.Repeat("b", 64)+`"}}`), 0o600))
+
+	c, stdout, _ := newApp(setupTest(t))
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "mirror", "generate",
+		"--base-url", "https://example.com/envoy", "--release-date", "2025-01-01",
+		"--merge", existing, "-o", existing, dir}))
+	require.Empty(t, stdout)
+
+	b, err := os.ReadFile(existing)
+	require.NoError(t, err)
+	var evs version.ReleaseVersions
+	require.NoError(t, json.Unmarshal(b, &evs))
+	require.Equal(t, map[version.PatchVersion]version.Release{
+		"1.31.2": {ReleaseDate: "2024-09-19", Tarballs: map[version.Platform]version.TarballURL{
+			"linux/amd64": "https://example.com/envoy/envoy-v1.31.2-linux-amd64.tar.xz",
+		}},
+		"1.32.0": {ReleaseDate: "2025-01-01", Tarballs: map[version.Platform]version.TarballURL{
+			"linux/amd64": "https://example.com/envoy/envoy-v1.32.0-linux-amd64.tar.xz",
+		}},
+	}, evs.Versions)
+	require.Len(t, evs.SHA256Sums, 2)
+}
+
+func TestFuncEMirrorGenerate_Stdout(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "envoy-v1.32.0-linux-amd64.tar.xz"), []byte("a"), 0o600))
+
+	c, stdout, _ := newApp(setupTest(t))
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "mirror", "generate",
+		"--base-url", "https://example.com/envoy", "--release-date", "2025-01-01", dir}))
+	require.Equal(t, `{
+  "versions": {
+    "1.32.0": {
+      "releaseDate": "2025-01-01",
+      "tarballs": {
+        "linux/amd64": "https://example.com/envoy/envoy-v1.32.0-linux-amd64.tar.xz"
+      }
+    }
+  },
+  "sha256sums": {
+    "envoy-v1.32.0-linux-amd64.tar.xz": "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"
+  }
+}
+`, stdout.String())
+}
+
+func TestFuncEMirrorGenerate_Validates(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing dir",
+			args:        []string{"--base-url", "https://example.com/envoy"},
+			expectedErr: "missing <dir> argument",
+		},
+		{
+			name:        "missing base URL",
+			args:        []string{dir},
+			expectedErr: "missing --base-url",
+		},
+		{
+			name:        "relative base URL",
+			args:        []string{"--base-url", "envoy", dir},
+			expectedErr: `invalid --base-url: "envoy" should look like "https://example.com/envoy"`,
+		},
+		{
+			name:        "invalid release date",
+			args:        []string{"--base-url", "https://example.com/envoy", "--release-date", "01/31/2024", dir},
+			expectedErr: `invalid --release-date: "01/31/2024" should look like "2024-01-31"`,
+		},
+		{
+			name:        "short dev commit",
+			args:        []string{"--base-url", "https://example.com/envoy", "--dev-commit-sha", "92c6", dir},
+			expectedErr: `invalid --dev-commit-sha: "92c6" should have at least 8 characters`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _, _ := newApp(setupTest(t))
+			err := c.Run(t.Context(), append([]string{"func-e", "mirror", "generate"}, tc.args...))
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestFuncEMirrorServe(t *testing.T) {
+	t.Run("nothing to serve", func(t *testing.T) {
+		c, _, _ := newApp(setupTest(t))
+		err := c.Run(t.Context(), []string{"func-e", "mirror", "serve", "--listen", "127.0.0.1:0"})
+		require.EqualError(t, err, `no Envoy versions to serve: install them with "func-e use" or "func-e install"`)
+	})
+
+	t.Run("serves installed versions", func(t *testing.T) {
+		o := setupTest(t)
+		binDir := filepath.Join(o.EnvoyVersionsDir(), "1.31.2", "bin")
+		require.NoError(t, os.MkdirAll(binDir, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, "envoy"), []byte("fake"), 0o700)) //nolint:gosec // fake binary
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		addr := ln.Addr().String()
+		require.NoError(t, ln.Close())
+
+		ctx, cancel := context.WithCancel(t.Context())
+		errCh := make(chan error, 1)
+		go func() {
+			c, _, _ := newApp(o)
+			errCh <- c.Run(ctx, []string{"func-e", "mirror", "serve", "--listen", addr})
+		}()
+
+		var evs version.ReleaseVersions
+		require.Eventually(t, func() bool {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+addr+"/envoy-versions.json", nil)
+			require.NoError(t, err)
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return false
+			}
+			defer res.Body.Close()
+			return json.NewDecoder(res.Body).Decode(&evs) == nil
+		}, 5*time.Second, 50*time.Millisecond)
+		require.Contains(t, evs.Versions, version.PatchVersion("1.31.2"))
+		require.NoError(t, evs.Validate())
+
+		cancel()
+		require.NoError(t, <-errCh)
+	})
+}
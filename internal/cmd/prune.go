@@ -0,0 +1,132 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// NewPruneCmd returns a command that removes installed Envoy versions according to retention policies.
+func NewPruneCmd(o *globals.GlobalOpts) *cli.Command {
+	var keepPerMinor int
+	var olderThan version.ReleaseDate
+	return &cli.Command{
+		Name:     "prune",
+		Usage:    "Removes installed Envoy versions no longer needed",
+		HideHelp: true,
+		Description: `Removes installed versions beyond the latest --keep-per-minor patches of
+each minor version, as well as those released before --older-than.
+Without either, nothing is removed.
+
+The current version, as shown by the "versions" command, is always kept.
+When it is a minor version, a constraint, "latest" or "stable", the
+installed version it selects is kept. Versions in use by a running Envoy
+are skipped.
+
+Example:
+$ func-e prune --keep-per-minor 2 --older-than 2024-01-01 --dry-run`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:        "keep-per-minor",
+				Usage:       "Number of latest patch versions to keep per minor version. 0 keeps all",
+				Destination: &keepPerMinor,
+			},
+			&cli.StringFlag{
+				Name:  "older-than",
+				Usage: "Remove versions released before this date (YYYY-MM-DD)",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print what would be removed without removing it",
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if keepPerMinor < 0 {
+				return ctx, NewValidationError(fmt.Sprintf("invalid --keep-per-minor: %d must not be negative", keepPerMinor))
+			}
+			if d := c.String("older-than"); d != "" {
+				if _, err := time.Parse("2006-01-02", d); err != nil {
+					return ctx, NewValidationError(fmt.Sprintf("invalid --older-than: %q should look like %q", d, "2024-01-31"))
+				}
+				olderThan = version.ReleaseDate(d)
+			}
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			rows, err := getInstalledVersions(o.EnvoyVersionsDir())
+			if err != nil {
+				return err
+			}
+
+			currentVersion, _, err := envoy.CurrentVersion(o.DataHome, o.EnvoyVersionFile(), o.EnvoyVersionFileSource())
+			if err != nil {
+				return err
+			}
+
+			inUse, err := versionsInUse(ctx, o.EnvoyVersionsDir())
+			if err != nil {
+				return err
+			}
+
+			var toRemove []version.PatchVersion
+			for _, v := range pruneVersions(rows, currentVersion, keepPerMinor, olderThan) {
+				if pid, ok := inUse[v]; ok {
+					o.Logf("skipping %s: in use by Envoy process %d\n", v, pid)
+					continue
+				}
+				toRemove = append(toRemove, v)
+			}
+			if len(toRemove) == 0 {
+				o.Logf("nothing to prune\n")
+				return nil
+			}
+			return removeVersions(o, toRemove, c.Bool("dry-run"))
+		},
+	}
+}
+
+// pruneVersions returns the installed versions not retained by the given policies, newest first.
+//
+// A version is retained when it is one of the latest keepPerMinor patches of its minor version (all when zero) and
+// not released before olderThan (when set). The current version is always retained, resolved like "versions" does
+// with currentPatchVersion.
+func pruneVersions(rows []versionReleaseDate, current version.Version, keepPerMinor int, olderThan version.ReleaseDate) []version.PatchVersion {
+	installed := make([]version.PatchVersion, 0, len(rows))
+	for _, vr := range rows { //nolint:gocritic
+		installed = append(installed, vr.version)
+	}
+
+	keep := currentPatchVersion(installed, current)
+
+	// Order each minor version's patches from highest to lowest, so that the first ones are kept.
+	sorted := make([]versionReleaseDate, len(rows))
+	copy(sorted, rows)
+	sort.Slice(sorted, func(i, j int) bool {
+		return version.ComparePatchVersions(sorted[i].version, sorted[j].version) > 0
+	})
+
+	var result []version.PatchVersion
+	kept := map[version.MinorVersion]int{}
+	for _, vr := range sorted { //nolint:gocritic
+		minor := vr.version.ToMinor()
+		kept[minor]++
+		switch {
+		case vr.version == keep:
+		case keepPerMinor > 0 && kept[minor] > keepPerMinor:
+			result = append(result, vr.version)
+		case olderThan != "" && vr.releaseDate < olderThan:
+			result = append(result, vr.version)
+		}
+	}
+	return result
+}
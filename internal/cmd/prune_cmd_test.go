@@ -0,0 +1,121 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+func TestFuncEPrune_FlagsValidate(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "negative keep-per-minor",
+			args:        []string{"func-e", "prune", "--keep-per-minor", "-1"},
+			expectedErr: "invalid --keep-per-minor: -1 must not be negative",
+		},
+		{
+			name:        "invalid older-than",
+			args:        []string{"func-e", "prune", "--older-than", "yesterday"},
+			expectedErr: `invalid --older-than: "yesterday" should look like "2024-01-31"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			err := c.Run(t.Context(), tc.args)
+
+			require.EqualError(t, err, tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncEPrune(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		setup          func(t *testing.T, o *globals.GlobalOpts)
+		expectedStdout string
+		expectedLeft   []string
+	}{
+		{
+			name: "no flags removes nothing",
+			args: []string{"func-e", "prune"},
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				t.Helper()
+				require.NoError(t, os.WriteFile(filepath.Join(o.ConfigHome, "envoy-version"), []byte("1.1.2"), 0o600))
+			},
+			expectedStdout: "nothing to prune\n",
+			expectedLeft:   []string{"1.1.2", "1.2.1", "1.2.2"},
+		},
+		{
+			name:           "keeps latest patch and current version",
+			args:           []string{"func-e", "prune", "--keep-per-minor", "1"},
+			expectedStdout: "nothing to prune\n",
+			expectedLeft:   []string{"1.1.2", "1.2.1", "1.2.2"},
+		},
+		{
+			name: "removes patches beyond the latest",
+			args: []string{"func-e", "prune", "--keep-per-minor", "1"},
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				t.Helper()
+				require.NoError(t, os.WriteFile(filepath.Join(o.ConfigHome, "envoy-version"), []byte("1.1.2"), 0o600))
+			},
+			expectedStdout: "removed $VERSIONS_DIR/1.2.1\n",
+			expectedLeft:   []string{"1.1.2", "1.2.2"},
+		},
+		{
+			name: "current minor version keeps its latest patch",
+			args: []string{"func-e", "prune", "--older-than", "2021-02-01"},
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				t.Helper()
+				require.NoError(t, os.WriteFile(filepath.Join(o.ConfigHome, "envoy-version"), []byte("1.2"), 0o600))
+			},
+			expectedStdout: "removed $VERSIONS_DIR/1.2.1\nremoved $VERSIONS_DIR/1.1.2\n",
+			expectedLeft:   []string{"1.2.2"},
+		},
+		{
+			name:           "dry run",
+			args:           []string{"func-e", "prune", "--older-than", "2021-02-01", "--dry-run"},
+			expectedStdout: "would remove $VERSIONS_DIR/1.2.2\nwould remove $VERSIONS_DIR/1.1.2\n",
+			expectedLeft:   []string{"1.1.2", "1.2.1", "1.2.2"},
+		},
+		{
+			name:           "skips version in use",
+			args:           []string{"func-e", "prune", "--older-than", "2021-02-01"},
+			setup:          func(t *testing.T, o *globals.GlobalOpts) { startInstalledEnvoy(t, o, "1.2.2") },
+			expectedStdout: "removed $VERSIONS_DIR/1.1.2\n",
+			expectedLeft:   []string{"1.2.1", "1.2.2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTestVersions(t)
+			if tc.setup != nil {
+				tc.setup(t, o)
+			}
+			c, stdout, stderr := newApp(o)
+			require.NoError(t, c.Run(t.Context(), tc.args))
+			require.Contains(t, stdout.String(), strings.ReplaceAll(tc.expectedStdout, "$VERSIONS_DIR", o.EnvoyVersionsDir()))
+			require.Empty(t, stderr)
+			require.Equal(t, tc.expectedLeft, installedVersions(t, o))
+		})
+	}
+}
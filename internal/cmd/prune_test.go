@@ -0,0 +1,93 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestPruneVersions(t *testing.T) {
+	rows := []versionReleaseDate{
+		{"1.1.2", "2021-01-31"},
+		{"1.2.1", "2021-01-30"},
+		{"1.2.2", "2021-01-31"},
+		{"1.2.10", "2021-03-01"},
+		{"1.2.10_debug", "2021-03-01"},
+	}
+
+	tests := []struct {
+		name         string
+		current      version.Version
+		keepPerMinor int
+		olderThan    version.ReleaseDate
+		expected     []version.PatchVersion
+	}{
+		{
+			name:         "keeps latest patch per minor",
+			keepPerMinor: 1,
+			expected:     []version.PatchVersion{"1.2.2", "1.2.1"},
+		},
+		{
+			name:         "keeps two patches per minor",
+			keepPerMinor: 2,
+			expected:     []version.PatchVersion{"1.2.1"},
+		},
+		{
+			name:         "keeps current patch version",
+			current:      version.PatchVersion("1.2.1"),
+			keepPerMinor: 1,
+			expected:     []version.PatchVersion{"1.2.2"},
+		},
+		{
+			name:         "keeps latest installed patch of current minor version",
+			current:      version.MinorVersion("1.2"),
+			keepPerMinor: 1,
+			expected:     []version.PatchVersion{"1.2.2", "1.2.1"},
+		},
+		{
+			name:      "keeps latest installed version meeting current constraint",
+			current:   version.Constraint("<1.2.10"),
+			olderThan: "2021-02-01",
+			expected:  []version.PatchVersion{"1.2.1", "1.1.2"},
+		},
+		{
+			name:      "older than",
+			olderThan: "2021-02-01",
+			expected:  []version.PatchVersion{"1.2.2", "1.2.1", "1.1.2"},
+		},
+		{
+			name:      "older than keeps current",
+			current:   version.PatchVersion("1.1.2"),
+			olderThan: "2021-02-01",
+			expected:  []version.PatchVersion{"1.2.2", "1.2.1"},
+		},
+		{
+			name:         "keep all",
+			keepPerMinor: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := pruneVersions(rows, tc.current, tc.keepPerMinor, tc.olderThan)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestPruneVersions_MixedDigits(t *testing.T) {
+	rows := []versionReleaseDate{
+		{"1.9.9", "2021-01-30"},
+		{"1.9.10", "2021-01-31"},
+		{"1.10.0", "2021-01-31"},
+		{"1.10.2", "2021-02-01"},
+	}
+
+	actual := pruneVersions(rows, nil, 1, "")
+	require.Equal(t, []version.PatchVersion{"1.10.0", "1.9.9"}, actual)
+}
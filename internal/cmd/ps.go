@@ -0,0 +1,91 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/admin"
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+// NewPsCmd returns a command that lists the running Envoy processes started by func-e.
+func NewPsCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:     "ps",
+		Usage:    "Lists running Envoy processes started by func-e",
+		HideHelp: true,
+		Description: `Lists each running Envoy tagged with a run ID, with its process ID,
+Envoy version, admin port, uptime and resident memory (RSS).
+
+Use "stop [runID]" to shut one down.`,
+		Action: func(ctx context.Context, c *cli.Command) error {
+			procs, err := admin.FindEnvoyProcesses(ctx)
+			if err != nil {
+				return err
+			}
+			if len(procs) == 0 {
+				return nil
+			}
+			sort.Slice(procs, func(i, j int) bool { return procs[i].RunID < procs[j].RunID })
+
+			versionsDir := o.EnvoyVersionsDir()
+			// Process executables are reported with symlinks resolved, e.g. /tmp on macOS.
+			if resolved, err := filepath.EvalSymlinks(versionsDir); err == nil {
+				versionsDir = resolved
+			}
+
+			w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "RUN ID\tPID\tVERSION\tADMIN PORT\tUPTIME\tRSS")
+			for _, ep := range procs {
+				uptime, rss := "-", "-"
+				if p, err := process.NewProcessWithContext(ctx, int32(ep.Pid)); err == nil { //nolint:gosec // pids fit
+					if createTime, err := p.CreateTimeWithContext(ctx); err == nil {
+						uptime = time.Since(time.UnixMilli(createTime)).Round(time.Second).String()
+					}
+					if mem, err := p.MemoryInfoWithContext(ctx); err == nil {
+						rss = envoy.FormatBytes(int64(mem.RSS)) //nolint:gosec // RSS fits
+					}
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", ep.RunID, ep.Pid,
+					processVersion(o, versionsDir, ep), adminPort(ep), uptime, rss)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+// processVersion returns the Envoy version of the process, from its installation directory or else the run it
+// was started by.
+func processVersion(o *globals.GlobalOpts, versionsDir string, ep admin.EnvoyProcess) string {
+	if v, ok := installedVersionOf(versionsDir, ep.EnvoyPath); ok {
+		return v.String()
+	}
+	if info, err := envoy.ReadRunInfo(o.EnvoyRunDir(ep.RunID)); err == nil && info.Pid == ep.Pid {
+		return orDash(info.EnvoyVersion)
+	}
+	return "-"
+}
+
+// adminPort returns the port Envoy wrote to its admin address file, or a dash if unknown.
+func adminPort(ep admin.EnvoyProcess) string {
+	if ep.AdminAddressPath == "" {
+		return "-"
+	}
+	port, err := admin.ReadAdminPort(ep.AdminAddressPath)
+	if err != nil {
+		return "-"
+	}
+	return strconv.Itoa(port)
+}
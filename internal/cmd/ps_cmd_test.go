@@ -0,0 +1,34 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncEPs(t *testing.T) {
+	o := setupTest(t)
+	runID, _ := startAdminEnvoy(t, o, installFakeEnvoy(t, o, "1.2.1"), o.EnvoyRunDir)
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "ps"}))
+	require.Empty(t, stderr)
+
+	// Other tests may be running Envoy concurrently, so only look at ours.
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	require.Equal(t, []string{"RUN", "ID", "PID", "VERSION", "ADMIN", "PORT", "UPTIME", "RSS"}, strings.Fields(lines[0]))
+	row := regexp.MustCompile(`^` + runID + `\s+\d+\s+1\.2\.1\s+\d+\s+\d+\S*s\s+[\d.]+ [KMG]?i?B$`)
+	var matched []string
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, runID) {
+			matched = append(matched, line)
+		}
+	}
+	require.Len(t, matched, 1)
+	require.Regexp(t, row, matched[0])
+}
@@ -41,6 +41,7 @@ Envoy's console output writes to "stdout.log" and "stderr.log" in the run direct
 			if err := runtime.EnsureEnvoyVersion(ctx, o); err != nil {
 				return ctx, NewValidationError(err.Error())
 			}
+			runtime.WarnIfUnsupported(ctx, o, o.EnvoyVersion)
 			return ctx, nil
 		},
 		Action: func(ctx context.Context, c *cli.Command) error {
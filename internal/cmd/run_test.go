@@ -104,6 +104,21 @@ func TestFuncERun_TeesConsoleToLogs(t *testing.T) {
 	require.Equal(t, stderrStream, string(stderrLogBytes))
 }
 
+// TestFuncERun_RunsWhenRunInfoFails ensures Envoy runs even if its run metadata can't be written.
+func TestFuncERun_RunsWhenRunInfoFails(t *testing.T) {
+	o := setupTest(t)
+	o.EnvoyPath = fakeEnvoyBin
+	o.RunDir, o.TempDir = t.TempDir(), t.TempDir()
+	// A non-empty directory where run.json should be, so it can't be written.
+	require.NoError(t, os.MkdirAll(filepath.Join(o.RunDir, "run.json", "dir"), 0o700))
+
+	c, _, stderr := newApp(o)
+	runWithInvalidConfig(t, c)
+
+	require.Contains(t, stderr.String(), "failed to write run info: couldn't write "+filepath.Join(o.RunDir, "run.json"))
+	require.Contains(t, stderr.String(), "At least one of --config-path or --config-yaml or Options::configProto() should be non-empty")
+}
+
 func TestFuncERun_ReadsHomeVersionFile(t *testing.T) {
 	o := setupTest(t)
 	o.EnvoyVersion = "" // pretend this is an initial setup
@@ -144,6 +159,22 @@ func TestFuncERun_CreatesHomeVersionFile(t *testing.T) {
 	require.Equal(t, version.LastKnownEnvoyMinor.String(), string(writtenVersion))
 }
 
+func TestFuncERun_WarnsWhenOutOfSupport(t *testing.T) {
+	o := setupTest(t)
+	o.Out = new(bytes.Buffer)
+	o.GetCachedEnvoyVersions = func(context.Context) (*version.ReleaseVersions, error) {
+		return &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+			version.LastKnownEnvoy: {ReleaseDate: "2020-12-31"},
+		}}, nil
+	}
+
+	c, _, _ := newApp(o)
+	runWithInvalidConfig(t, c)
+
+	require.Contains(t, o.Out.(*bytes.Buffer).String(),
+		fmt.Sprintf("warning: Envoy %s is out of support since 2021-12-31: consider \"func-e use stable\"\n", version.LastKnownEnvoy))
+}
+
 // runWithInvalidConfig intentionally has envoy quit. This allows tests to not have to interrupt envoy to proceed
 func runWithInvalidConfig(t *testing.T, c *cli.Command) {
 	t.Helper()
@@ -0,0 +1,220 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+// runFiles are the files func-e writes into a run directory, in the order they are listed.
+var runFiles = []string{envoy.RunInfoFile, "stdout.log", "stderr.log", "config_dump.json"}
+
+// NewRunsCmd returns a command that lists past runs of Envoy.
+func NewRunsCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:     "runs",
+		Usage:    `List past runs of the "run" command`,
+		HideHelp: true,
+		Description: `Lists each run ID in the state directory, newest first, with its start
+time, Envoy version, exit status, duration and arguments.
+
+Use "runs show [runID]" for the paths to a run's logs.`,
+		Action: func(ctx context.Context, c *cli.Command) error {
+			rows, err := getRuns(o.EnvoyRunsDir())
+			if err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+
+			w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "RUN ID\tSTART\tVERSION\tSTATUS\tDURATION\tARGS")
+			for _, r := range rows { //nolint:gocritic
+				if r.info == nil { // e.g. a run before func-e recorded run.json
+					_, _ = fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\n", r.runID, r.startTime().Format(time.DateTime))
+					continue
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.runID, r.startTime().Format(time.DateTime),
+					orDash(r.info.EnvoyVersion), runStatus(ctx, r.info), runDuration(r.info), strings.Join(r.info.Args, " "))
+			}
+			return w.Flush()
+		},
+		Commands: []*cli.Command{
+			{
+				Name:      "show",
+				Usage:     "Show the details and files of a past run",
+				ArgsUsage: "[runID]",
+				HideHelp:  true,
+				Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+					return ctx, validateRunIDArg(c.Args().First())
+				},
+				Action: func(ctx context.Context, c *cli.Command) error {
+					runID := c.Args().First()
+					runDir := o.EnvoyRunDir(runID)
+					if _, err := os.Stat(runDir); os.IsNotExist(err) {
+						return fmt.Errorf("run %s not found in %s", runID, o.EnvoyRunsDir())
+					} else if err != nil {
+						return err
+					}
+					info, err := envoy.ReadRunInfo(runDir)
+					if err != nil && !os.IsNotExist(err) {
+						return err
+					}
+					return printRun(ctx, c.Root().Writer, runID, runDir, info)
+				},
+			},
+		},
+	}
+}
+
+// validateRunIDArg ensures a [runID] argument is present and can't escape the runs directory.
+func validateRunIDArg(runID string) error {
+	if runID == "" {
+		return NewValidationError("missing [runID] argument")
+	}
+	if strings.ContainsAny(runID, `/\`) || runID == "." || runID == ".." {
+		return NewValidationError(fmt.Sprintf("invalid [runID] argument: %q", runID))
+	}
+	return nil
+}
+
+type runRow struct {
+	runID  string
+	runDir string
+	// info is nil when the run has no readable envoy.RunInfoFile.
+	info    *envoy.RunInfo
+	modTime time.Time
+}
+
+// startTime returns when the run started, approximated by the directory's mtime if unknown.
+func (r *runRow) startTime() time.Time {
+	if r.info != nil {
+		return r.info.StartTime
+	}
+	return r.modTime
+}
+
+// getRuns returns the runs in runsDir, newest first.
+func getRuns(runsDir string) ([]runRow, error) {
+	var rows []runRow
+	files, err := os.ReadDir(runsDir)
+	if os.IsNotExist(err) {
+		return rows, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		i, err := f.Info()
+		if !f.IsDir() || err != nil {
+			continue
+		}
+		runDir := filepath.Join(runsDir, f.Name())
+		info, err := envoy.ReadRunInfo(runDir)
+		if err != nil {
+			info = nil
+		}
+		rows = append(rows, runRow{runID: f.Name(), runDir: runDir, info: info, modTime: i.ModTime()})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if ti, tj := rows[i].startTime(), rows[j].startTime(); !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return rows[i].runID > rows[j].runID
+	})
+	return rows, nil
+}
+
+// runStatus summarizes whether Envoy is running, exited, or never started.
+func runStatus(ctx context.Context, info *envoy.RunInfo) string {
+	switch {
+	case info.ExitStatus != nil:
+		return fmt.Sprintf("exit %d", *info.ExitStatus)
+	case info.EndTime != nil:
+		return "not started"
+	case !runActive(ctx, info):
+		return "unknown"
+	case info.Pid == 0:
+		return "starting"
+	}
+	return "running"
+}
+
+// runActive returns false when the run ended, including when func-e was killed before it could record that.
+func runActive(ctx context.Context, info *envoy.RunInfo) bool {
+	if info.EndTime != nil {
+		return false
+	}
+	if info.Pid == 0 { // Envoy is starting
+		return true
+	}
+	ok, err := process.PidExistsWithContext(ctx, int32(info.Pid)) //nolint:gosec // pids never overflow int32
+	return err != nil || ok
+}
+
+func runDuration(info *envoy.RunInfo) string {
+	if info.EndTime == nil {
+		return "-"
+	}
+	return info.Duration().Round(time.Millisecond).String()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func printRun(ctx context.Context, out io.Writer, runID, runDir string, info *envoy.RunInfo) error {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintf(w, "run ID:\t%s\n", runID)
+	_, _ = fmt.Fprintf(w, "run dir:\t%s\n", runDir)
+	if info != nil {
+		_, _ = fmt.Fprintf(w, "status:\t%s\n", runStatus(ctx, info))
+		_, _ = fmt.Fprintf(w, "start time:\t%s\n", info.StartTime.Format(time.RFC3339))
+		if info.EndTime != nil {
+			_, _ = fmt.Fprintf(w, "end time:\t%s\n", info.EndTime.Format(time.RFC3339))
+		}
+		_, _ = fmt.Fprintf(w, "duration:\t%s\n", runDuration(info))
+		_, _ = fmt.Fprintf(w, "Envoy version:\t%s\n", orDash(info.EnvoyVersion))
+		_, _ = fmt.Fprintf(w, "Envoy path:\t%s\n", info.EnvoyPath)
+		if info.Pid != 0 {
+			_, _ = fmt.Fprintf(w, "pid:\t%d\n", info.Pid)
+		}
+		_, _ = fmt.Fprintf(w, "args:\t%s\n", strings.Join(info.Args, " "))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(out, "files:")
+	for _, name := range runFiles {
+		if path := filepath.Join(runDir, name); fileExists(path) {
+			_, _ = fmt.Fprintf(out, "  %s\n", path)
+		}
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
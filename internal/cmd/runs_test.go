@@ -0,0 +1,196 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFuncERuns(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T) *globals.GlobalOpts
+		expected string
+	}{
+		{
+			name:     "no runs",
+			setup:    setupTest,
+			expected: "",
+		},
+		{
+			name:  "newest first",
+			setup: setupTestRuns,
+			expected: `RUN ID               START                VERSION  STATUS       DURATION  ARGS
+20251012_150000_000  2025-10-12 15:00:00  1.35.0   not started  0s        -c missing.yaml
+20251012_143053_700  2025-10-12 14:30:53  1.35.0   exit 0       1m30s     -c envoy.yaml
+old                  2025-01-01 00:00:00  -        -            -         -
+`,
+		},
+		{
+			name: "legacy layout",
+			setup: func(t *testing.T) *globals.GlobalOpts {
+				t.Helper()
+				o := setupTest(t)
+				o.HomeDir = o.StateHome
+				writeTestRun(t, o, "1760279453000000000", testRunInfo("1760279453000000000"))
+				return o
+			},
+			expected: `RUN ID               START                VERSION  STATUS  DURATION  ARGS
+1760279453000000000  2025-10-12 14:30:53  1.35.0   exit 0  1m30s     -c envoy.yaml
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := tc.setup(t)
+			c, stdout, stderr := newApp(o)
+			require.NoError(t, c.Run(t.Context(), []string{"func-e", "runs"}))
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncERunsShow(t *testing.T) {
+	o := setupTestRuns(t)
+	runDir := o.EnvoyRunDir("20251012_143053_700")
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "runs", "show", "20251012_143053_700"}))
+	require.Equal(t, `run ID:        20251012_143053_700
+run dir:       `+runDir+`
+status:        exit 0
+start time:    2025-10-12T14:30:53Z
+end time:      2025-10-12T14:32:23Z
+duration:      1m30s
+Envoy version: 1.35.0
+Envoy path:    /opt/envoy/bin/envoy
+pid:           1234
+args:          -c envoy.yaml
+files:
+  `+filepath.Join(runDir, "run.json")+`
+  `+filepath.Join(runDir, "stderr.log")+`
+`, stdout.String())
+	require.Empty(t, stderr)
+}
+
+func TestFuncERunsShow_Errors(t *testing.T) {
+	o := setupTestRuns(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing run ID",
+			args:        []string{"func-e", "runs", "show"},
+			expectedErr: "missing [runID] argument",
+		},
+		{
+			name:        "run ID with path separator",
+			args:        []string{"func-e", "runs", "show", "../runs"},
+			expectedErr: `invalid [runID] argument: "../runs"`,
+		},
+		{
+			name:        "unknown run ID",
+			args:        []string{"func-e", "runs", "show", "unknown"},
+			expectedErr: "run unknown not found in " + o.EnvoyRunsDir(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			require.EqualError(t, c.Run(t.Context(), tc.args), tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncERun_RecordsRun(t *testing.T) {
+	o := setupTest(t)
+	o.EnvoyPath = fakeEnvoyBin
+
+	c, _, _ := newApp(o)
+	o.Out = io.Discard
+	runWithInvalidConfig(t, c)
+	runID := o.RunID
+
+	info, err := envoy.ReadRunInfo(o.RunDir)
+	require.NoError(t, err)
+	require.Equal(t, runID, info.RunID)
+	require.Equal(t, version.LastKnownEnvoy.String(), info.EnvoyVersion)
+	require.Equal(t, fakeEnvoyBin, info.EnvoyPath)
+	require.Empty(t, info.Args)
+	require.NotZero(t, info.Pid)
+	require.NotNil(t, info.ExitStatus)
+	require.Equal(t, 1, *info.ExitStatus)
+
+	c, stdout, _ := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "runs"}))
+	lines := strings.Split(stdout.String(), "\n")
+	require.Len(t, lines, 3) // header, run and trailing newline
+	require.Contains(t, lines[1], runID)
+	require.Contains(t, lines[1], "exit 1")
+}
+
+// setupTestRuns creates runs with and without run.json in the state directory.
+func setupTestRuns(t *testing.T) *globals.GlobalOpts {
+	t.Helper()
+	o := setupTest(t)
+
+	writeTestRun(t, o, "20251012_143053_700", testRunInfo("20251012_143053_700"))
+
+	notStarted := testRunInfo("20251012_150000_000")
+	notStarted.StartTime = time.Date(2025, 10, 12, 15, 0, 0, 0, time.UTC)
+	notStarted.EndTime = &notStarted.StartTime
+	notStarted.ExitStatus = nil
+	notStarted.Pid = 0
+	notStarted.Args = []string{"-c", "missing.yaml"}
+	writeTestRun(t, o, "20251012_150000_000", notStarted)
+
+	old := o.EnvoyRunDir("old")
+	require.NoError(t, os.MkdirAll(old, 0o750))
+	mtime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	require.NoError(t, os.Chtimes(old, mtime, mtime))
+	return o
+}
+
+func testRunInfo(runID string) *envoy.RunInfo {
+	exitStatus := 0
+	start := time.Date(2025, 10, 12, 14, 30, 53, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	return &envoy.RunInfo{
+		RunID:        runID,
+		StartTime:    start,
+		EndTime:      &end,
+		EnvoyVersion: "1.35.0",
+		EnvoyPath:    "/opt/envoy/bin/envoy",
+		Args:         []string{"-c", "envoy.yaml"},
+		Pid:          1234,
+		ExitStatus:   &exitStatus,
+	}
+}
+
+func writeTestRun(t *testing.T, o *globals.GlobalOpts, runID string, info *envoy.RunInfo) {
+	t.Helper()
+	runDir := o.EnvoyRunDir(runID)
+	require.NoError(t, os.MkdirAll(runDir, 0o750))
+	require.NoError(t, envoy.WriteRunInfo(runDir, info))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "stderr.log"), nil, 0o600))
+}
@@ -0,0 +1,127 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/admin"
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+// stopPollInterval is how often "stop" checks whether Envoy exited.
+const stopPollInterval = 100 * time.Millisecond
+
+// NewStopCmd returns a command that shuts down a running Envoy started by func-e.
+func NewStopCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:      "stop",
+		Usage:     "Stops a running Envoy started by func-e",
+		ArgsUsage: "[runID]",
+		HideHelp:  true,
+		Description: `The '[runID]' is from the "ps" command. This asks Envoy to exit using
+its admin endpoint "/quitquitquit", or sends SIGTERM if the admin API isn't
+reachable. Envoy is killed if it is still running after --timeout.
+
+Example:
+$ func-e stop --timeout 30s 20251012_143053_700`,
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "How long to wait for Envoy to exit before killing it",
+				Value: 10 * time.Second,
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if err := validateRunIDArg(c.Args().First()); err != nil {
+				return ctx, err
+			}
+			if timeout := c.Duration("timeout"); timeout <= 0 {
+				return ctx, NewValidationError(fmt.Sprintf("invalid --timeout: %s must be positive", timeout))
+			}
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			ep, err := admin.FindEnvoyProcess(ctx, c.Args().First())
+			if err != nil {
+				return err
+			}
+			p, err := process.NewProcessWithContext(ctx, int32(ep.Pid)) //nolint:gosec // pids fit
+			if err != nil {
+				return err
+			}
+
+			if err = quitEnvoy(ctx, o, ep); err != nil {
+				o.Logf("sending SIGTERM to Envoy process %d: %v\n", ep.Pid, err)
+				if err = p.TerminateWithContext(ctx); err != nil {
+					return fmt.Errorf("failed to stop Envoy process %d: %w", ep.Pid, err)
+				}
+			}
+
+			timeout := c.Duration("timeout")
+			if awaitExit(ctx, p, timeout) {
+				o.Logf("stopped Envoy process %d\n", ep.Pid)
+				return nil
+			}
+			o.Logf("killing Envoy process %d, still running after %s\n", ep.Pid, timeout)
+			if err = p.KillWithContext(ctx); err != nil {
+				return fmt.Errorf("failed to kill Envoy process %d: %w", ep.Pid, err)
+			}
+			return nil
+		},
+	}
+}
+
+// quitEnvoy asks Envoy to exit using its admin API.
+func quitEnvoy(ctx context.Context, o *globals.GlobalOpts, ep *admin.EnvoyProcess) error {
+	if ep.AdminAddressPath == "" {
+		return errors.New("no admin address")
+	}
+	port, err := admin.ReadAdminPort(ep.AdminAddressPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, adminAddressTimeout)
+	defer cancel()
+	url := fmt.Sprintf("http://127.0.0.1:%d/quitquitquit", port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, http.NoBody)
+	if err != nil {
+		return err
+	}
+	resp, err := o.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error Envoy admin URL %s: status_code=%d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// awaitExit returns true if the process exits within the timeout.
+func awaitExit(ctx context.Context, p *process.Process, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(stopPollInterval)
+	defer ticker.Stop()
+	for {
+		if running, err := p.IsRunningWithContext(ctx); err == nil && !running {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncEStop(t *testing.T) {
+	o := setupTest(t)
+	runID, exited := startAdminEnvoy(t, o, fakeEnvoyBin, o.EnvoyRunDir)
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "stop", runID}))
+	requireExited(t, exited)
+	require.Regexp(t, `^stopped Envoy process \d+\n$`, stdout.String())
+	require.Empty(t, stderr)
+}
+
+func TestFuncEStop_NoAdmin(t *testing.T) {
+	o := setupTest(t)
+	runID := fmt.Sprintf("stop-%d", time.Now().UnixNano())
+	exited := startFakeEnvoy(t, fakeEnvoyBin, "--config-yaml", "static_resources: {}", "--", "--run-id", runID)
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "stop", runID}))
+	requireExited(t, exited)
+	require.Regexp(t, `^sending SIGTERM to Envoy process \d+: no admin address\nstopped Envoy process \d+\n$`, stdout.String())
+	require.Empty(t, stderr)
+}
+
+func TestFuncEStop_Kills(t *testing.T) {
+	o := setupTest(t)
+	runID := fmt.Sprintf("stop-%d", time.Now().UnixNano())
+	// Simulate an Envoy stuck in shutdown, which ignores SIGTERM.
+	cmd := exec.CommandContext(t.Context(), "sh", "-c",
+		fmt.Sprintf("trap '' TERM; while :; do sleep 1; done; echo -- --run-id %s", runID))
+	require.NoError(t, cmd.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	})
+	time.Sleep(100 * time.Millisecond) // let the shell install its trap
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "stop", "--timeout", "300ms", runID}))
+	requireExited(t, exited)
+	require.Regexp(t, `killing Envoy process \d+, still running after 300ms\n$`, stdout.String())
+	require.Empty(t, stderr)
+}
+
+func TestFuncEStop_Validates(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing run ID",
+			args:        []string{"func-e", "stop"},
+			expectedErr: "missing [runID] argument",
+		},
+		{
+			name:        "invalid timeout",
+			args:        []string{"func-e", "stop", "--timeout", "0s", "unknown"},
+			expectedErr: "invalid --timeout: 0s must be positive",
+		},
+		{
+			name:        "unknown run ID",
+			args:        []string{"func-e", "stop", "unknown"},
+			expectedErr: "no Envoy process found with --run-id unknown",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			require.EqualError(t, c.Run(t.Context(), tc.args), tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func requireExited(t *testing.T, exited <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process didn't exit")
+	}
+}
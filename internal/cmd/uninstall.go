@@ -0,0 +1,121 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/admin"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// NewUninstallCmd returns a command that removes an installed Envoy version.
+func NewUninstallCmd(o *globals.GlobalOpts) *cli.Command {
+	versionsDir := "$FUNC_E_DATA_HOME/envoy-versions/"
+
+	var v version.PatchVersion
+	return &cli.Command{
+		Name:      "uninstall",
+		Usage:     "Removes an installed Envoy [version]",
+		ArgsUsage: "[version]",
+		HideHelp:  true,
+		Description: `The '[version]' is from the "versions" command. This deletes
+` + versionsDir + `[version], unless a running Envoy uses it.
+
+Example:
+$ func-e uninstall --dry-run ` + version.LastKnownEnvoy.String(),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Print what would be removed without removing it",
+			},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			parsed, err := version.NewVersion("[version] argument", c.Args().First())
+			if err != nil {
+				return ctx, NewValidationError(err.Error())
+			}
+			pv, ok := parsed.(version.PatchVersion)
+			if !ok || pv == version.DevLatest {
+				return ctx, NewValidationError(fmt.Sprintf("invalid [version] argument: %q should look like %q or %q",
+					parsed, version.LastKnownEnvoy, version.Dev))
+			}
+			v = pv
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if _, err := os.Stat(filepath.Join(o.EnvoyVersionsDir(), v.String())); os.IsNotExist(err) {
+				return fmt.Errorf("version %s is not installed", v)
+			} else if err != nil {
+				return err
+			}
+
+			inUse, err := versionsInUse(ctx, o.EnvoyVersionsDir())
+			if err != nil {
+				return err
+			}
+			if pid, ok := inUse[v]; ok {
+				return fmt.Errorf("version %s is in use by Envoy process %d", v, pid)
+			}
+			return removeVersions(o, []version.PatchVersion{v}, c.Bool("dry-run"))
+		},
+	}
+}
+
+// versionsInUse returns the installed versions whose binary is executing,
+// mapped to the pid of an Envoy process using it.
+func versionsInUse(ctx context.Context, versionsDir string) (map[version.PatchVersion]int, error) {
+	procs, err := admin.FindEnvoyProcesses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process executables are reported with symlinks resolved, e.g. /tmp on macOS.
+	if resolved, err := filepath.EvalSymlinks(versionsDir); err == nil {
+		versionsDir = resolved
+	}
+
+	inUse := map[version.PatchVersion]int{}
+	for _, p := range procs {
+		if v, ok := installedVersionOf(versionsDir, p.EnvoyPath); ok {
+			inUse[v] = p.Pid
+		}
+	}
+	return inUse, nil
+}
+
+// installedVersionOf returns the version whose installation directory in
+// versionsDir contains envoyPath.
+func installedVersionOf(versionsDir, envoyPath string) (version.PatchVersion, bool) {
+	rel, err := filepath.Rel(versionsDir, envoyPath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	dir, _, _ := strings.Cut(filepath.ToSlash(rel), "/")
+	return version.PatchVersion(dir), true
+}
+
+// removeVersions deletes the installation directories of the given versions,
+// or only prints them when dryRun is set.
+func removeVersions(o *globals.GlobalOpts, versions []version.PatchVersion, dryRun bool) error {
+	for _, v := range versions {
+		installPath := filepath.Join(o.EnvoyVersionsDir(), v.String())
+		if dryRun {
+			_, _ = fmt.Fprintf(o.Out, "would remove %s\n", installPath)
+			continue
+		}
+		if err := os.RemoveAll(installPath); err != nil {
+			return fmt.Errorf("unable to remove directory %q: %w", installPath, err)
+		}
+		o.Logf("removed %s\n", installPath)
+	}
+	return nil
+}
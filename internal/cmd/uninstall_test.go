@@ -0,0 +1,176 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFuncEUninstall_VersionValidates(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct{ name, version, expectedErr string }{
+		{
+			name:        "version empty",
+			expectedErr: "missing [version] argument",
+		},
+		{
+			name:        "version invalid",
+			version:     "a.b.c",
+			expectedErr: fmt.Sprintf(`invalid [version] argument: "a.b.c" should look like %q or %q`, version.LastKnownEnvoy, version.LastKnownEnvoyMinor),
+		},
+		{
+			name:        "minor version",
+			version:     "1.2",
+			expectedErr: fmt.Sprintf(`invalid [version] argument: "1.2" should look like %q or "dev"`, version.LastKnownEnvoy),
+		},
+		{
+			name:        "dev-latest",
+			version:     "dev-latest",
+			expectedErr: fmt.Sprintf(`invalid [version] argument: "dev-latest" should look like %q or "dev"`, version.LastKnownEnvoy),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			err := c.Run(t.Context(), []string{"func-e", "uninstall", tc.version})
+
+			require.EqualError(t, err, tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncEUninstall(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedStdout string
+		expectedErr    string
+		expectedLeft   []string
+	}{
+		{
+			name:           "removes version",
+			args:           []string{"func-e", "uninstall", "1.2.1"},
+			expectedStdout: "removed $VERSIONS_DIR/1.2.1\n",
+			expectedLeft:   []string{"1.1.2", "1.2.2"},
+		},
+		{
+			name:           "dry run",
+			args:           []string{"func-e", "uninstall", "--dry-run", "1.2.1"},
+			expectedStdout: "would remove $VERSIONS_DIR/1.2.1\n",
+			expectedLeft:   []string{"1.1.2", "1.2.1", "1.2.2"},
+		},
+		{
+			name:         "not installed",
+			args:         []string{"func-e", "uninstall", "1.3.1"},
+			expectedErr:  "version 1.3.1 is not installed",
+			expectedLeft: []string{"1.1.2", "1.2.1", "1.2.2"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTestVersions(t)
+			c, stdout, stderr := newApp(o)
+			err := c.Run(t.Context(), tc.args)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, strings.ReplaceAll(tc.expectedStdout, "$VERSIONS_DIR", o.EnvoyVersionsDir()), stdout.String())
+			}
+			require.Empty(t, stderr)
+			require.Equal(t, tc.expectedLeft, installedVersions(t, o))
+		})
+	}
+}
+
+func TestFuncEUninstall_RefusesVersionInUse(t *testing.T) {
+	o := setupTestVersions(t)
+	startInstalledEnvoy(t, o, "1.2.1")
+
+	c, _, _ := newApp(o)
+	err := c.Run(t.Context(), []string{"func-e", "uninstall", "1.2.1"})
+	require.ErrorContains(t, err, "version 1.2.1 is in use by Envoy process")
+	require.Equal(t, []string{"1.1.2", "1.2.1", "1.2.2"}, installedVersions(t, o))
+}
+
+// startInstalledEnvoy runs a copy of the fake Envoy from the install directory of v, tagged as func-e does.
+func startInstalledEnvoy(t *testing.T, o *globals.GlobalOpts, v string) {
+	t.Helper()
+	startFakeEnvoy(t, installFakeEnvoy(t, o, v), "--config-yaml", "static_resources: {}", "--", "--run-id", "in-use")
+}
+
+// installFakeEnvoy copies the fake Envoy into the install directory of v, returning its path.
+func installFakeEnvoy(t *testing.T, o *globals.GlobalOpts, v string) string {
+	t.Helper()
+	envoyPath := filepath.Join(o.EnvoyVersionsDir(), v, "bin", "envoy")
+	require.NoError(t, os.MkdirAll(filepath.Dir(envoyPath), 0o700))
+	bin, err := os.ReadFile(fakeEnvoyBin)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(envoyPath, bin, 0o700)) //nolint:gosec // must be executable
+	return envoyPath
+}
+
+// startFakeEnvoy runs the fake Envoy at envoyPath until the test ends, returning once it is started. The
+// returned channel is closed when the process exits.
+func startFakeEnvoy(t *testing.T, envoyPath string, args ...string) <-chan struct{} {
+	t.Helper()
+	cmd := exec.CommandContext(t.Context(), envoyPath, args...)
+	stderr, err := cmd.StderrPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+
+	started := false
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), "starting main dispatch loop") {
+			started = true
+			break
+		}
+	}
+
+	// Reap the process as soon as it exits, so that it isn't seen as running.
+	exited := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, stderr)
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	})
+
+	if !started {
+		t.Fatal("fake Envoy exited before starting")
+	}
+	return exited
+}
+
+func installedVersions(t *testing.T, o *globals.GlobalOpts) []string {
+	t.Helper()
+	entries, err := os.ReadDir(o.EnvoyVersionsDir())
+	require.NoError(t, err)
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Name())
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/runtime"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// NewUpgradeCmd returns a command that upgrades the current Envoy version to its latest patch.
+func NewUpgradeCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:     "upgrade",
+		Usage:    "Upgrades the current Envoy version to its latest patch",
+		HideHelp: true,
+		Description: `Compares the current version, as shown by the "versions" command, with the
+latest patch of its minor version. If newer, this installs it and updates
+$PWD/.envoy-version or $FUNC_E_CONFIG_HOME/envoy-version, whichever set
+the current version. --minor also considers newer minor versions.
+
+A minor version, a constraint, "latest" or "stable" already use the latest
+match each time they are used, so there is nothing to upgrade.
+
+--check only reports an upgrade, failing if there is one, for use in CI.
+
+Example:
+$ func-e upgrade --check`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "Fail if there is an upgrade, instead of installing it",
+			},
+			&cli.BoolFlag{
+				Name:  "minor",
+				Usage: "Upgrade to the latest minor version, not only the latest patch",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			// Create base XDG directories before any file operations
+			if err := o.Mkdirs(); err != nil {
+				return err
+			}
+
+			currentVersion, source, err := envoy.CurrentVersion(o.DataHome, o.EnvoyVersionFile(), o.EnvoyVersionFileSource())
+			if err != nil {
+				return err
+			} else if currentVersion == nil {
+				return NewValidationError(`there is no current version to upgrade: choose one with "func-e use"`)
+			}
+			current, ok := currentVersion.(version.PatchVersion)
+			if _, isRelease := current.Parse(); !ok || !isRelease {
+				o.Logf("%s set by %s is looked up each time it is used: nothing to upgrade\n", currentVersion, source)
+				return nil
+			}
+
+			evs, err := o.GetEnvoyVersions(ctx)
+			if err != nil {
+				return err
+			}
+			var available []version.PatchVersion
+			for v, r := range evs.Versions {
+				if r.HasPlatform(o.Platform) {
+					available = append(available, v)
+				}
+			}
+			upgrade := findUpgrade(available, current, c.Bool("minor"))
+			if upgrade == "" {
+				o.Logf("%s set by %s is up to date\n", current, source)
+				return nil
+			}
+
+			if c.Bool("check") {
+				command := "func-e upgrade"
+				if c.Bool("minor") {
+					command += " --minor"
+				}
+				return fmt.Errorf("%s set by %s can be upgraded to %s: run %q", current, source, upgrade, command)
+			}
+
+			o.EnvoyVersion = upgrade
+			if _, err = envoy.InstallIfNeeded(ctx, o); err != nil {
+				return err
+			}
+			runtime.WarnIfUnsupported(ctx, o, upgrade)
+			if source == envoy.CurrentVersionVar {
+				o.Logf("installed Envoy %s, but %s can't be updated: set it to %s\n", upgrade, source, upgrade)
+				return nil
+			}
+			if err = envoy.WriteCurrentVersion(upgrade, o.ConfigHome, o.EnvoyVersionFile()); err != nil {
+				return err
+			}
+			o.Logf("upgraded Envoy %s to %s in %s\n", current, upgrade, source)
+			return nil
+		},
+	}
+}
+
+// findUpgrade returns the latest version newer than current, or empty if there is none. Without minor, this is the
+// latest patch of the minor version of current. A debug version only upgrades to another debug version.
+func findUpgrade(available []version.PatchVersion, current version.PatchVersion, minor bool) version.PatchVersion {
+	parsed, _ := current.Parse()
+	var candidates []version.PatchVersion
+	for _, v := range available {
+		p, ok := v.Parse()
+		if !ok || p.Debug != parsed.Debug || !minor && p.CompareMinor(parsed) != 0 {
+			continue
+		}
+		if p.Compare(parsed) > 0 {
+			candidates = append(candidates, v)
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	return slices.MaxFunc(candidates, version.ComparePatchVersions)
+}
@@ -0,0 +1,145 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFuncEUpgrade(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		setup           func(t *testing.T, o *globals.GlobalOpts)
+		expectedStdout  string
+		expectedErr     string
+		expectedVersion string
+		// expectedWorkingDirVersion is the content of $PWD/.envoy-version, if written by setup.
+		expectedWorkingDirVersion string
+		expectedInstalls          []string
+	}{
+		{
+			name:            "check",
+			args:            []string{"func-e", "upgrade", "--check"},
+			setup:           writeConfigVersion("1.29.3"),
+			expectedErr:     `1.29.3 set by $FUNC_E_CONFIG_HOME/envoy-version can be upgraded to 1.29.5: run "func-e upgrade"`,
+			expectedVersion: "1.29.3",
+		},
+		{
+			name:            "check minor",
+			args:            []string{"func-e", "upgrade", "--check", "--minor"},
+			setup:           writeConfigVersion("1.29.5"),
+			expectedErr:     `1.29.5 set by $FUNC_E_CONFIG_HOME/envoy-version can be upgraded to 1.30.1: run "func-e upgrade --minor"`,
+			expectedVersion: "1.29.5",
+		},
+		{
+			name:             "upgrades config file",
+			args:             []string{"func-e", "upgrade"},
+			setup:            writeConfigVersion("1.29.3"),
+			expectedStdout:   "upgraded Envoy 1.29.3 to 1.29.5 in $FUNC_E_CONFIG_HOME/envoy-version\n",
+			expectedVersion:  "1.29.5",
+			expectedInstalls: []string{"1.29.5"},
+		},
+		{
+			name:             "upgrades minor",
+			args:             []string{"func-e", "upgrade", "--minor"},
+			setup:            writeConfigVersion("1.29.3"),
+			expectedStdout:   "upgraded Envoy 1.29.3 to 1.30.1 in $FUNC_E_CONFIG_HOME/envoy-version\n",
+			expectedVersion:  "1.30.1",
+			expectedInstalls: []string{"1.30.1"},
+		},
+		{
+			name: "upgrades working directory file",
+			args: []string{"func-e", "upgrade"},
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				writeConfigVersion("1.29.5")(t, o)
+				t.Chdir(t.TempDir())
+				require.NoError(t, os.WriteFile(".envoy-version", []byte("1.29.3"), 0o600))
+			},
+			expectedStdout:            "upgraded Envoy 1.29.3 to 1.29.5 in $PWD/.envoy-version\n",
+			expectedVersion:           "1.29.5", // unchanged
+			expectedWorkingDirVersion: "1.29.5",
+			expectedInstalls:          []string{"1.29.5"},
+		},
+		{
+			name: "environment variable",
+			args: []string{"func-e", "upgrade"},
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				writeConfigVersion("1.29.3")(t, o)
+				t.Setenv("ENVOY_VERSION", "1.29.4")
+			},
+			expectedStdout:   "installed Envoy 1.29.5, but $ENVOY_VERSION can't be updated: set it to 1.29.5\n",
+			expectedVersion:  "1.29.3",
+			expectedInstalls: []string{"1.29.5"},
+		},
+		{
+			name:            "up to date",
+			args:            []string{"func-e", "upgrade", "--check"},
+			setup:           writeConfigVersion("1.29.5"),
+			expectedStdout:  "1.29.5 set by $FUNC_E_CONFIG_HOME/envoy-version is up to date\n",
+			expectedVersion: "1.29.5",
+		},
+		{
+			name:            "minor version",
+			args:            []string{"func-e", "upgrade", "--check"},
+			setup:           writeConfigVersion("1.29"),
+			expectedStdout:  "1.29 set by $FUNC_E_CONFIG_HOME/envoy-version is looked up each time it is used: nothing to upgrade\n",
+			expectedVersion: "1.29",
+		},
+		{
+			name:        "no current version",
+			args:        []string{"func-e", "upgrade"},
+			expectedErr: `there is no current version to upgrade: choose one with "func-e use"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTest(t)
+			overrideAvailableVersions(t, o, []version.PatchVersion{"1.29.3", "1.29.4", "1.29.5", "1.30.1"})
+			if tc.setup != nil {
+				tc.setup(t, o)
+			}
+
+			c, stdout, stderr := newApp(o)
+			err := c.Run(t.Context(), tc.args)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Contains(t, stdout.String(), tc.expectedStdout)
+			require.Empty(t, stderr)
+
+			if tc.expectedVersion != "" {
+				written, err := os.ReadFile(filepath.Join(o.ConfigHome, "envoy-version"))
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedVersion, string(written))
+			}
+			if tc.expectedWorkingDirVersion != "" {
+				written, err := os.ReadFile(".envoy-version")
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedWorkingDirVersion, string(written))
+			}
+			for _, v := range tc.expectedInstalls {
+				require.DirExists(t, filepath.Join(o.DataHome, "envoy-versions", v))
+			}
+		})
+	}
+}
+
+// writeConfigVersion returns a setup function that sets the current version in $FUNC_E_CONFIG_HOME/envoy-version.
+func writeConfigVersion(v string) func(t *testing.T, o *globals.GlobalOpts) {
+	return func(t *testing.T, o *globals.GlobalOpts) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(o.ConfigHome, "envoy-version"), []byte(v), 0o600))
+	}
+}
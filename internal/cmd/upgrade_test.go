@@ -0,0 +1,39 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFindUpgrade(t *testing.T) {
+	available := []version.PatchVersion{
+		"1.9.10", "1.9.2", "1.10.0", "1.10.1", "1.10.2_debug", "1.11.0", "1.11.1_debug", version.Dev,
+	}
+
+	tests := []struct {
+		name     string
+		current  version.PatchVersion
+		minor    bool
+		expected version.PatchVersion
+	}{
+		{name: "latest patch", current: "1.9.2", expected: "1.9.10"},
+		{name: "up to date", current: "1.9.10"},
+		{name: "newer than available", current: "1.9.11"},
+		{name: "latest minor", current: "1.9.2", minor: true, expected: "1.11.0"},
+		{name: "latest minor up to date", current: "1.11.0", minor: true},
+		{name: "debug patch", current: "1.10.0_debug", expected: "1.10.2_debug"},
+		{name: "debug minor", current: "1.10.0_debug", minor: true, expected: "1.11.1_debug"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, findUpgrade(available, tc.current, tc.minor))
+		})
+	}
+}
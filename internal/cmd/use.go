@@ -31,7 +31,10 @@ func NewUseCmd(o *globals.GlobalOpts) *cli.Command {
 The Envoy [version] installs on-demand into `+versionsDir+`[version]
 if needed. You may also exclude the patch component of the [version]
 to use the latest patch version or to download the binary if it is
-not already downloaded.
+not already downloaded. A constraint, such as '>=1.30 <1.32', '~1.31'
+or '^1.30', uses the latest version that meets it. 'latest' uses the
+newest version, and 'stable' the newest patch of the minor version
+before it. These are looked up again each time they are used.
 
 This updates %s or %s with [version],
 depending on which is present.
@@ -51,7 +54,7 @@ $ func-e use %s`, currentVersionWorkingDirFile, currentVersionConfigFile, versio
 			if err := o.Mkdirs(); err != nil {
 				return err
 			}
-			// The argument could be a MinorVersion (ex. 1.19) or a PatchVersion (ex. 1.19.3)
+			// The argument could be a MinorVersion (ex. 1.19), a Channel (ex. stable) or a PatchVersion (ex. 1.19.3)
 			// We need to download and install a patch version
 			if o.EnvoyVersion, err = runtime.EnsurePatchVersion(ctx, o, v); err != nil {
 				return err
@@ -59,6 +62,7 @@ $ func-e use %s`, currentVersionWorkingDirFile, currentVersionConfigFile, versio
 			if _, err = envoy.InstallIfNeeded(ctx, o); err != nil {
 				return err
 			}
+			runtime.WarnIfUnsupported(ctx, o, o.EnvoyVersion)
 			if v == version.DevLatest {
 				v = version.Dev
 			}
@@ -91,6 +91,30 @@ func TestFuncEUse(t *testing.T) {
 			version: "1.18",
 			stdout:  "downloading",
 		},
+		{
+			name: "stable",
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				t.Helper()
+				overrideAvailableVersions(t, o, []version.PatchVersion{"1.29.4", "1.29.5", "1.30.1"})
+			},
+			version:      "stable",
+			stdout:       "looking up the stable Envoy version\ndownloading",
+			savedVersion: "stable",
+		},
+		{
+			name: "out of support",
+			setup: func(t *testing.T, o *globals.GlobalOpts) {
+				t.Helper()
+				o.GetCachedEnvoyVersions = func(_ context.Context) (*version.ReleaseVersions, error) {
+					return &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+						version.LastKnownEnvoy: {ReleaseDate: "2020-12-31"},
+					}}, nil
+				}
+			},
+			version:      version.LastKnownEnvoy.String(),
+			stdout:       fmt.Sprintf("warning: Envoy %s is out of support since 2021-12-31: consider \"func-e use stable\"\n", version.LastKnownEnvoy),
+			savedVersion: version.LastKnownEnvoy.String(),
+		},
 		{
 			name: "minor not upgraded",
 			setup: func(t *testing.T, o *globals.GlobalOpts) {
@@ -0,0 +1,178 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/tetratelabs/func-e/internal/envoy"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/runtime"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+const versionsFlag = "--versions"
+
+// NewValidateCmd returns a command that validates an Envoy configuration without running it.
+func NewValidateCmd(o *globals.GlobalOpts) *cli.Command {
+	var versions []version.Version
+	var args []string
+	stopOnFirstArg := 0
+	return &cli.Command{
+		Name:         "validate",
+		Usage:        "Validate Envoy configuration given [arguments...] without running it",
+		ArgsUsage:    "[--versions versions] [arguments...]",
+		StopOnNthArg: &stopOnFirstArg,
+		HideHelp:     true,
+		Description: `Runs Envoy with "--mode validate" and the '[arguments...]', such as
+"-c envoy.yaml", then reports whether each version accepted the configuration.
+
+The version validated is the same one "run" would use. To validate against
+other versions in parallel, pass them comma-separated to --versions, before
+the '[arguments...]'. Each version is downloaded and installed, if necessary.
+
+Example:
+$ func-e validate --versions ` + version.LastKnownEnvoy.ToMinor().String() + `,` + version.LastKnownEnvoy.String() + ` -c envoy.yaml`,
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			var versionsArg string
+			var err error
+			if versionsArg, args, err = splitVersionsArg(c.Args().Slice()); err != nil {
+				return ctx, NewValidationError(err.Error())
+			}
+			if versionsArg != "" {
+				for _, s := range strings.Split(versionsArg, ",") {
+					v, err := version.NewVersion(versionsFlag, strings.TrimSpace(s))
+					if err != nil {
+						return ctx, NewValidationError(err.Error())
+					}
+					versions = append(versions, v)
+				}
+				return ctx, nil
+			}
+			if o.EnvoyPath != "" { // custom binary, skip version resolution
+				return ctx, nil
+			}
+			if err = runtime.EnsureEnvoyVersion(ctx, o); err != nil {
+				return ctx, NewValidationError(err.Error())
+			}
+			return ctx, nil
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			results, err := validateVersions(ctx, o, versions, args)
+			if err != nil {
+				return err
+			}
+
+			var failed []string
+			for _, r := range results {
+				_, _ = io.Copy(o.Out, &r.log)
+				if r.err == nil {
+					_, _ = fmt.Fprintf(c.Root().Writer, "%s: OK\n", r.name)
+					continue
+				}
+				failed = append(failed, r.name)
+				_, _ = fmt.Fprintf(c.Root().Writer, "%s: FAILED (%v)\n", r.name, r.err)
+				scanner := bufio.NewScanner(&r.out)
+				for scanner.Scan() {
+					_, _ = fmt.Fprintf(c.Root().Writer, "  %s\n", scanner.Text())
+				}
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("configuration is invalid for Envoy %s", strings.Join(failed, ", "))
+			}
+			return nil
+		},
+	}
+}
+
+// splitVersionsArg removes a leading --versions flag from Envoy's arguments.
+func splitVersionsArg(args []string) (versions string, rest []string, err error) {
+	switch {
+	case len(args) > 0 && args[0] == versionsFlag:
+		if len(args) == 1 || args[1] == "" {
+			return "", nil, fmt.Errorf("missing %s value", versionsFlag)
+		}
+		return args[1], args[2:], nil
+	case len(args) > 0 && strings.HasPrefix(args[0], versionsFlag+"="):
+		if versions = strings.TrimPrefix(args[0], versionsFlag+"="); versions == "" {
+			return "", nil, fmt.Errorf("missing %s value", versionsFlag)
+		}
+		return versions, args[1:], nil
+	}
+	return "", args, nil
+}
+
+// validation is the outcome of validating the configuration with one Envoy binary.
+type validation struct {
+	// name is the Envoy version, or path when --envoy-path is set.
+	name string
+	// log is what func-e logged while installing Envoy.
+	log bytes.Buffer
+	// out is Envoy's console output.
+	out bytes.Buffer
+	err error
+}
+
+// validateVersions validates the configuration with each version in parallel, or only the current version when
+// there are none. Results are in the order of the versions.
+func validateVersions(ctx context.Context, o *globals.GlobalOpts, versions []version.Version, args []string) ([]*validation, error) {
+	if len(versions) == 0 {
+		r := &validation{name: o.EnvoyVersion.String()}
+		if o.EnvoyPath != "" {
+			r.name = o.EnvoyPath
+		}
+		vo := *o
+		validateVersion(ctx, &vo, r, args)
+		return []*validation{r}, nil
+	}
+
+	// Resolve all patch versions first, so that the same version isn't installed concurrently.
+	var patchVersions []version.PatchVersion
+	seen := map[version.PatchVersion]bool{}
+	for _, v := range versions {
+		pv, err := runtime.EnsurePatchVersion(ctx, o, v)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[pv] {
+			seen[pv] = true
+			patchVersions = append(patchVersions, pv)
+		}
+	}
+
+	results := make([]*validation, len(patchVersions))
+	var wg sync.WaitGroup
+	for i, pv := range patchVersions {
+		r := &validation{name: pv.String()}
+		results[i] = r
+		// Each version gets its own copy of the options, as installation writes to them.
+		vo := *o
+		vo.EnvoyVersion = pv
+		vo.EnvoyPath = ""
+		wg.Go(func() {
+			validateVersion(ctx, &vo, r, args)
+		})
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// validateVersion installs the Envoy version in o, if needed, and validates the configuration with it.
+func validateVersion(ctx context.Context, o *globals.GlobalOpts, r *validation, args []string) {
+	o.Out = &r.log
+	if o.EnvoyPath == "" {
+		if o.EnvoyPath, r.err = envoy.InstallIfNeeded(ctx, o); r.err != nil {
+			return
+		}
+	}
+	r.err = envoy.Validate(ctx, o.EnvoyPath, args, &r.out)
+}
@@ -0,0 +1,112 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestFuncEValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expected    string
+		expectedLog string
+	}{
+		{
+			name:        "current version",
+			args:        []string{"func-e", "validate", "--config-yaml", "static_resources: {}"},
+			expected:    version.LastKnownEnvoy.String() + ": OK\n",
+			expectedLog: "downloading",
+		},
+		{
+			name:     "versions",
+			args:     []string{"func-e", "validate", "--versions", "1.2.1,1.2.2", "--config-yaml", "static_resources: {}"},
+			expected: "1.2.1: OK\n1.2.2: OK\n",
+		},
+		{
+			name:        "minor version resolves to the installed patch once",
+			args:        []string{"func-e", "validate", "--versions=1.2,1.2.2", "--config-yaml", "static_resources: {}"},
+			expected:    "1.2.2: OK\n",
+			expectedLog: "using last installed version",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTest(t)
+			installFakeEnvoy(t, o, "1.2.1")
+			installFakeEnvoy(t, o, "1.2.2")
+
+			c, stdout, stderr := newApp(o)
+			o.Out = new(bytes.Buffer) // separate status messages from results
+			require.NoError(t, c.Run(t.Context(), tc.args))
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+			require.Contains(t, o.Out.(*bytes.Buffer).String(), tc.expectedLog)
+		})
+	}
+}
+
+func TestFuncEValidate_EnvoyPath(t *testing.T) {
+	o := setupTest(t)
+	o.EnvoyPath = fakeEnvoyBin
+
+	c, stdout, _ := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "validate", "--config-yaml", "static_resources: {}"}))
+	require.Equal(t, fakeEnvoyBin+": OK\n", stdout.String())
+}
+
+func TestFuncEValidate_Invalid(t *testing.T) {
+	o := setupTest(t)
+	installFakeEnvoy(t, o, "1.2.1")
+	installFakeEnvoy(t, o, "1.2.2")
+
+	c, stdout, _ := newApp(o)
+	o.Out = io.Discard // ignore status messages
+	err := c.Run(t.Context(), []string{"func-e", "validate", "--versions", "1.2.1,1.2.2"})
+	require.EqualError(t, err, "configuration is invalid for Envoy 1.2.1, 1.2.2")
+	expectedFailure := `: FAILED (envoy exited with status 1)
+  initializing epoch 0
+  exiting
+  At least one of --config-path or --config-yaml or Options::configProto() should be non-empty
+`
+	require.Equal(t, "1.2.1"+expectedFailure+"1.2.2"+expectedFailure, stdout.String())
+}
+
+func TestFuncEValidate_Validates(t *testing.T) {
+	o := setupTest(t)
+
+	tests := []struct {
+		name        string
+		args        []string
+		expectedErr string
+	}{
+		{
+			name:        "missing versions",
+			args:        []string{"func-e", "validate", "--versions"},
+			expectedErr: "missing --versions value",
+		},
+		{
+			name:        "invalid versions",
+			args:        []string{"func-e", "validate", "--versions", "1.2.1,1.x", "-c", "envoy.yaml"},
+			expectedErr: `invalid --versions: "1.x" should look like "` + version.LastKnownEnvoy.String() + `" or "` + version.LastKnownEnvoy.ToMinor().String() + `"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, stderr := newApp(o)
+			require.EqualError(t, c.Run(t.Context(), tc.args), tc.expectedErr)
+			require.Empty(t, stdout)
+			require.Empty(t, stderr)
+		})
+	}
+}
@@ -5,16 +5,23 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
 	"os"
+	"path/filepath"
 	"sort"
 	"text/tabwriter"
 	"time"
 
 	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
 
 	"github.com/tetratelabs/func-e/internal/envoy"
 	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/runtime"
 	"github.com/tetratelabs/func-e/internal/version"
 )
 
@@ -30,6 +37,23 @@ func NewVersionsCmd(o *globals.GlobalOpts) *cli.Command {
 				Aliases: []string{"a"},
 				Usage:   "Show all versions including ones not yet installed",
 			},
+			&cli.BoolFlag{
+				Name:  "supported",
+				Usage: "Only show versions Envoy still supports with security fixes",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   `Output format: "text", "json" or "yaml"`,
+				Value:   outputText,
+			},
+		},
+		Commands: []*cli.Command{newVersionsValidateCmd(o)},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if output := c.String("output"); output != outputText && output != outputJSON && output != outputYAML {
+				return ctx, NewValidationError(fmt.Sprintf(`invalid --output %q, should be "text", "json" or "yaml"`, output))
+			}
+			return ctx, nil
 		},
 		Action: func(ctx context.Context, c *cli.Command) error {
 			rows, err := getInstalledVersions(o.EnvoyVersionsDir())
@@ -41,50 +65,199 @@ func NewVersionsCmd(o *globals.GlobalOpts) *cli.Command {
 			if err != nil {
 				return err
 			}
+			installed := make([]version.PatchVersion, 0, len(rows))
+			for _, vr := range rows { //nolint:gocritic
+				installed = append(installed, vr.version)
+			}
+			current := currentPatchVersion(installed, currentVersion)
 
+			var evs *version.ReleaseVersions
 			var dev *version.DevRelease
-			if c.Bool("all") {
-				evs, err := o.GetEnvoyVersions(ctx)
-				if err != nil {
-					return err
-				}
-				if err := addAvailableVersions(&rows, evs.Versions, o.Platform); err != nil {
+			if c.Bool("all") || c.Bool("supported") {
+				if evs, err = o.GetEnvoyVersions(ctx); err != nil {
 					return err
 				}
-				if evs.Dev != nil {
-					if _, ok := evs.Dev.Tarballs[o.Platform]; ok {
-						dev = evs.Dev
+				if c.Bool("all") {
+					if err := addAvailableVersions(&rows, evs.Versions, o.Platform); err != nil {
+						return err
+					}
+					if evs.Dev != nil {
+						if _, ok := evs.Dev.Tarballs[o.Platform]; ok {
+							dev = evs.Dev
+						}
 					}
 				}
+				if c.Bool("supported") {
+					rows = supportedVersions(rows, evs, time.Now())
+				}
 			}
 
 			// Sort so that new release dates appear first and on conflict choosing the higher version
 			sort.Slice(rows, func(i, j int) bool {
 				if rows[i].releaseDate == rows[j].releaseDate {
-					return rows[i].version > rows[j].version
+					return version.ComparePatchVersions(rows[i].version, rows[j].version) > 0
 				}
 				return rows[i].releaseDate > rows[j].releaseDate
 			})
 
+			if output := c.String("output"); output != outputText {
+				if evs == nil && o.GetCachedEnvoyVersions != nil { // for tarballs and support, without the network
+					evs, _ = o.GetCachedEnvoyVersions(ctx)
+				}
+				infos := getVersionInfos(o, rows, dev, evs, current, currentVersion, currentVersionSource)
+				return writeVersionInfos(c.Root().Writer, output, infos)
+			}
+
+			if _, ok := currentVersion.(version.PatchVersion); !ok && current != "" {
+				currentVersionSource += ": " + currentVersion.String() // show the minor version or constraint
+			}
+
 			// We use a tab writer to ensure we can format the current version
 			w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 1, ' ', tabwriter.AlignRight)
 			if dev != nil {
 				_, _ = fmt.Fprintf(w, "  dev %s (%s)\n", dev.ReleaseDate, dev.CommitSha[:8])
 			}
 			for _, vr := range rows { //nolint:gocritic
-				// TODO: handle when currentVersion is a MinorVersion
-				pv, ok := currentVersion.(version.PatchVersion)
-				if ok && vr.version == pv {
+				if current != "" && vr.version == current {
 					_, _ = fmt.Fprintf(w, "* %s %s (set by %s)\n", vr.version, vr.releaseDate, currentVersionSource)
 				} else {
 					_, _ = fmt.Fprintf(w, "  %s %s\n", vr.version, vr.releaseDate)
 				}
 			}
-			return w.Flush()
+			if err = w.Flush(); err != nil {
+				return err
+			}
+			if current != "" {
+				runtime.WarnIfUnsupported(ctx, o, current)
+			}
+			return nil
+		},
+	}
+}
+
+// versionInfo is a version listed by "versions --output json" or "yaml". Fields from the Envoy versions JSON are
+// empty when it isn't cached, unless "--all" or "--supported" looked it up.
+type versionInfo struct {
+	Version     version.PatchVersion `json:"version" yaml:"version"`
+	ReleaseDate version.ReleaseDate  `json:"releaseDate" yaml:"releaseDate"`
+	// CommitSha is only set for the dev build.
+	CommitSha string `json:"commitSha,omitempty" yaml:"commitSha,omitempty"`
+	Installed bool   `json:"installed" yaml:"installed"`
+	// Size is the size in bytes of the installed files.
+	Size       int64               `json:"size,omitempty" yaml:"size,omitempty"`
+	TarballURL version.TarballURL  `json:"tarballURL,omitempty" yaml:"tarballURL,omitempty"`
+	SHA256     version.SHA256Sum   `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	SupportEnd version.ReleaseDate `json:"supportEnd,omitempty" yaml:"supportEnd,omitempty"`
+	Current    bool                `json:"current" yaml:"current"`
+	// Source is where the current version was read from, such as "$ENVOY_VERSION", when Current.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Selection is the current version as written in Source, such as "1.31" or "stable", when Current.
+	Selection string `json:"selection,omitempty" yaml:"selection,omitempty"`
+}
+
+// getVersionInfos returns a versionInfo for dev, if not nil, then each row. evs is nil if it couldn't be read.
+func getVersionInfos(o *globals.GlobalOpts, rows []versionReleaseDate, dev *version.DevRelease, evs *version.ReleaseVersions,
+	current version.PatchVersion, currentVersion version.Version, source string,
+) []versionInfo {
+	infos := make([]versionInfo, 0, len(rows)+1)
+	if dev != nil {
+		info := versionInfo{Version: version.Dev, ReleaseDate: dev.ReleaseDate, CommitSha: dev.CommitSha, TarballURL: dev.Tarballs[o.Platform]}
+		info.SHA256 = evs.SHA256Sums[info.TarballURL.Tarball()]
+		infos = append(infos, info)
+	}
+	for _, vr := range rows { //nolint:gocritic
+		info := versionInfo{Version: vr.version, ReleaseDate: vr.releaseDate}
+		if evs != nil {
+			if info.TarballURL = evs.Versions[vr.version].Tarballs[o.Platform]; info.TarballURL != "" {
+				info.SHA256 = evs.SHA256Sums[info.TarballURL.Tarball()]
+			}
+			info.SupportEnd, _ = evs.SupportEnd(vr.version)
+		}
+		if current != "" && vr.version == current {
+			info.Current, info.Source, info.Selection = true, source, currentVersion.String()
+		}
+		infos = append(infos, info)
+	}
+	for i := range infos {
+		infos[i].Installed, infos[i].Size = installedSize(filepath.Join(o.EnvoyVersionsDir(), infos[i].Version.String()))
+	}
+	return infos
+}
+
+// installedSize returns true and the size of the files in dir, or false if it doesn't exist.
+func installedSize(dir string) (bool, int64) {
+	if _, err := os.Stat(dir); err != nil {
+		return false, 0
+	}
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if i, err := d.Info(); err == nil {
+				size += i.Size()
+			}
+		}
+		return nil
+	})
+	return true, size
+}
+
+// writeVersionInfos writes infos as output, which is outputJSON or outputYAML.
+func writeVersionInfos(w io.Writer, output string, infos []versionInfo) error {
+	if output == outputYAML {
+		e := yaml.NewEncoder(w)
+		e.SetIndent(2)
+		if err := e.Encode(infos); err != nil {
+			return err
+		}
+		return e.Close()
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(infos)
+}
+
+// newVersionsValidateCmd returns a command that reports every problem in an Envoy versions JSON, such as a mirror's.
+func newVersionsValidateCmd(o *globals.GlobalOpts) *cli.Command {
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "Validate an Envoy versions JSON, such as a mirror's",
+		ArgsUsage: "<url|file>",
+		HideHelp:  true,
+		Description: `Reads the Envoy versions JSON at the URL or file, and reports every problem
+in it, such as a tarball without a sha256sum, with its JSON path.
+
+Example:
+$ func-e versions validate envoy-versions.json`,
+		Action: func(ctx context.Context, c *cli.Command) error {
+			arg := c.Args().First()
+			if arg == "" {
+				return NewValidationError("missing <url|file> argument")
+			}
+			envoyVersionsURL, err := versionsURLOrFile(arg)
+			if err != nil {
+				return err
+			}
+			if _, err = envoy.NewGetVersions(o.HTTPClient, envoyVersionsURL, o.UserAgent)(ctx); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(c.Root().Writer, "%s: OK\n", arg)
+			return nil
 		},
 	}
 }
 
+// versionsURLOrFile returns arg if it is an http, https or file URL. Otherwise, it is a path, returned as a file URL.
+func versionsURLOrFile(arg string) (string, error) {
+	if u, err := url.Parse(arg); err == nil && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "file") {
+		return arg, nil
+	}
+	path, err := filepath.Abs(arg)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String(), nil
+}
+
 type versionReleaseDate struct {
 	version     version.PatchVersion
 	releaseDate version.ReleaseDate
@@ -111,6 +284,34 @@ func getInstalledVersions(versionsDir string) ([]versionReleaseDate, error) {
 	return rows, nil
 }
 
+// currentPatchVersion returns the patch version the current version selects from installed, or empty if there is
+// none. A version.MinorVersion selects its latest installed patch, a version.Constraint the latest installed
+// version meeting it, and a version.Channel what it selects of those installed, like "run" does when offline.
+func currentPatchVersion(installed []version.PatchVersion, current version.Version) version.PatchVersion {
+	switch cv := current.(type) {
+	case version.PatchVersion:
+		return cv
+	case version.MinorVersion:
+		return version.FindLatestPatchVersion(installed, cv)
+	case version.Constraint:
+		return version.FindLatestMatchingVersion(installed, cv)
+	case version.Channel:
+		return version.FindChannelVersion(installed, cv)
+	}
+	return ""
+}
+
+// supportedVersions returns the rows whose version Envoy still supports, including those whose support is unknown.
+func supportedVersions(rows []versionReleaseDate, evs *version.ReleaseVersions, now time.Time) []versionReleaseDate {
+	var supported []versionReleaseDate
+	for _, vr := range rows { //nolint:gocritic
+		if evs.IsSupported(vr.version, now) {
+			supported = append(supported, vr)
+		}
+	}
+	return supported
+}
+
 // addAvailableVersions adds remote Envoy versions valid for this platform to "rows", if they don't already exist
 func addAvailableVersions(rows *[]versionReleaseDate, remote map[version.PatchVersion]version.Release, p version.Platform) error {
 	existingVersions := make(map[version.PatchVersion]bool)
@@ -119,7 +320,7 @@ func addAvailableVersions(rows *[]versionReleaseDate, remote map[version.PatchVe
 	}
 
 	for k, v := range remote {
-		if _, ok := v.Tarballs[p]; ok && !existingVersions[k] {
+		if v.HasPlatform(p) && !existingVersions[k] {
 			if _, err := time.Parse("2006-01-02", string(v.ReleaseDate)); err != nil {
 				return fmt.Errorf("invalid releaseDate of version %q for platform %q: %w", k, p, err)
 			}
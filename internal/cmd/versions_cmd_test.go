@@ -5,13 +5,17 @@ package cmd_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/tetratelabs/func-e/internal/admin"
 	"github.com/tetratelabs/func-e/internal/globals"
 	"github.com/tetratelabs/func-e/internal/test/morerequire"
 	"github.com/tetratelabs/func-e/internal/version"
@@ -81,6 +85,66 @@ func TestFuncEVersions(t *testing.T) {
 			},
 			expected: "  1.2.2 2021-01-31\n  1.1.2 2021-01-31\n* 1.2.1 2021-01-30 (set by $ENVOY_VERSION)\n",
 		},
+		{
+			name: "current minor version",
+			args: []string{"func-e", "versions"},
+			setup: func(t *testing.T) *globals.GlobalOpts {
+				t.Helper()
+				o := setupTestVersions(t)
+				t.Setenv("ENVOY_VERSION", "1.2")
+				return o
+			},
+			expected: "* 1.2.2 2021-01-31 (set by $ENVOY_VERSION: 1.2)\n  1.1.2 2021-01-31\n  1.2.1 2021-01-30\n",
+		},
+		{
+			name: "current constraint",
+			args: []string{"func-e", "versions"},
+			setup: func(t *testing.T) *globals.GlobalOpts {
+				t.Helper()
+				o := setupTestVersions(t)
+				t.Chdir(t.TempDir())
+				require.NoError(t, os.WriteFile(".envoy-version", []byte(">=1.1 <1.2.2"), 0o600))
+				return o
+			},
+			expected: "  1.2.2 2021-01-31\n  1.1.2 2021-01-31\n* 1.2.1 2021-01-30 (set by $PWD/.envoy-version: >=1.1 <1.2.2)\n",
+		},
+		{
+			name: "current constraint not installed",
+			args: []string{"func-e", "versions"},
+			setup: func(t *testing.T) *globals.GlobalOpts {
+				t.Helper()
+				o := setupTestVersions(t)
+				t.Setenv("ENVOY_VERSION", "~1.3")
+				return o
+			},
+			expected: "  1.2.2 2021-01-31\n  1.1.2 2021-01-31\n  1.2.1 2021-01-30\n",
+		},
+		{
+			name: "current channel",
+			args: []string{"func-e", "versions"},
+			setup: func(t *testing.T) *globals.GlobalOpts {
+				t.Helper()
+				o := setupTestVersions(t)
+				t.Setenv("ENVOY_VERSION", "stable")
+				return o
+			},
+			expected: "  1.2.2 2021-01-31\n* 1.1.2 2021-01-31 (set by $ENVOY_VERSION: stable)\n  1.2.1 2021-01-30\n",
+		},
+		{
+			name: "same release date sorted by version",
+			args: []string{"func-e", "versions"},
+			setup: func(t *testing.T) *globals.GlobalOpts {
+				t.Helper()
+				o := setupTestVersions(t)
+				for _, v := range []string{"1.10.0", "1.9.9"} {
+					versionDir := filepath.Join(o.DataHome, "envoy-versions", v)
+					require.NoError(t, os.MkdirAll(versionDir, 0o700))
+					morerequire.RequireSetMtime(t, versionDir, "2021-01-31")
+				}
+				return o
+			},
+			expected: "  1.10.0 2021-01-31\n  1.9.9 2021-01-31\n  1.2.2 2021-01-31\n  1.1.2 2021-01-31\n* 1.2.1 2021-01-30 (set by $FUNC_E_CONFIG_HOME/envoy-version)\n",
+		},
 		{
 			name:     "all only remote",
 			args:     []string{"func-e", "versions", "-a"},
@@ -144,6 +208,195 @@ func TestFuncEVersions(t *testing.T) {
 	}
 }
 
+func TestFuncEVersions_Supported(t *testing.T) {
+	now := time.Now()
+	getVersions := func(_ context.Context) (*version.ReleaseVersions, error) {
+		return &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+			"1.1.0": {ReleaseDate: "2021-01-01"},
+			"1.2.0": {ReleaseDate: version.ReleaseDate(now.AddDate(0, -1, 0).Format("2006-01-02"))},
+		}}, nil
+	}
+	warning := "warning: Envoy 1.1.2 is out of support since 2022-01-01: consider \"func-e use stable\"\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		current  string
+		expected string
+	}{
+		{
+			name:     "all versions",
+			args:     []string{"func-e", "versions"},
+			current:  "1.2.1",
+			expected: "  1.2.2 2021-01-31\n  1.1.2 2021-01-31\n* 1.2.1 2021-01-30 (set by $FUNC_E_CONFIG_HOME/envoy-version)\n",
+		},
+		{
+			name:     "supported",
+			args:     []string{"func-e", "versions", "--supported"},
+			current:  "1.2.1",
+			expected: "  1.2.2 2021-01-31\n* 1.2.1 2021-01-30 (set by $FUNC_E_CONFIG_HOME/envoy-version)\n",
+		},
+		{
+			name:     "current out of support",
+			args:     []string{"func-e", "versions"},
+			current:  "1.1.2",
+			expected: "  1.2.2 2021-01-31\n* 1.1.2 2021-01-31 (set by $FUNC_E_CONFIG_HOME/envoy-version)\n  1.2.1 2021-01-30\n" + warning,
+		},
+		{
+			name:     "supported without current",
+			args:     []string{"func-e", "versions", "--supported"},
+			current:  "1.1.2",
+			expected: "  1.2.2 2021-01-31\n  1.2.1 2021-01-30\n" + warning,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTestVersions(t)
+			require.NoError(t, os.WriteFile(filepath.Join(o.ConfigHome, "envoy-version"), []byte(tc.current), 0o600))
+			o.GetEnvoyVersions = getVersions
+			o.GetCachedEnvoyVersions = getVersions
+
+			c, stdout, stderr := newApp(o)
+			require.NoError(t, c.Run(t.Context(), tc.args))
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+func TestFuncEVersions_Output(t *testing.T) {
+	sha256Sum := version.SHA256Sum(strings.Repeat("a", 64))
+	getCached := func(_ context.Context) (*version.ReleaseVersions, error) {
+		return &version.ReleaseVersions{
+			Versions: map[version.PatchVersion]version.Release{
+				"1.2.1": {ReleaseDate: "2021-01-30", Tarballs: map[version.Platform]version.TarballURL{
+					globals.DefaultPlatform: "https://example.com/envoy-v1.2.1.tar.xz",
+				}},
+			},
+			SHA256Sums: map[version.Tarball]version.SHA256Sum{"envoy-v1.2.1.tar.xz": sha256Sum},
+		}, nil
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:   "json",
+			output: "json",
+			expected: `[
+  {
+    "version": "1.2.2",
+    "releaseDate": "2021-01-31",
+    "installed": true,
+    "size": 5,
+    "supportEnd": "2022-01-30",
+    "current": true,
+    "source": "$ENVOY_VERSION",
+    "selection": "1.2"
+  },
+  {
+    "version": "1.1.2",
+    "releaseDate": "2021-01-31",
+    "installed": true,
+    "current": false
+  },
+  {
+    "version": "1.2.1",
+    "releaseDate": "2021-01-30",
+    "installed": true,
+    "tarballURL": "https://example.com/envoy-v1.2.1.tar.xz",
+    "sha256": "` + string(sha256Sum) + `",
+    "supportEnd": "2022-01-30",
+    "current": false
+  }
+]
+`,
+		},
+		{
+			name:   "yaml",
+			output: "yaml",
+			expected: `- version: 1.2.2
+  releaseDate: "2021-01-31"
+  installed: true
+  size: 5
+  supportEnd: "2022-01-30"
+  current: true
+  source: $ENVOY_VERSION
+  selection: "1.2"
+- version: 1.1.2
+  releaseDate: "2021-01-31"
+  installed: true
+  current: false
+- version: 1.2.1
+  releaseDate: "2021-01-30"
+  installed: true
+  tarballURL: https://example.com/envoy-v1.2.1.tar.xz
+  sha256: ` + string(sha256Sum) + `
+  supportEnd: "2022-01-30"
+  current: false
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := setupTestVersions(t)
+			o.GetCachedEnvoyVersions = getCached
+			versionDir := filepath.Join(o.DataHome, "envoy-versions", "1.2.2")
+			require.NoError(t, os.WriteFile(filepath.Join(versionDir, "envoy"), []byte("envoy"), 0o600))
+			morerequire.RequireSetMtime(t, versionDir, "2021-01-31")
+			t.Setenv("ENVOY_VERSION", "1.2")
+
+			c, stdout, stderr := newApp(o)
+			require.NoError(t, c.Run(t.Context(), []string{"func-e", "versions", "--output", tc.output}))
+			require.Equal(t, tc.expected, stdout.String())
+			require.Empty(t, stderr)
+		})
+	}
+}
+
+// TestFuncEVersions_OutputCurrentMinor ensures the patch a minor version selects is the current one, not the minor.
+func TestFuncEVersions_OutputCurrentMinor(t *testing.T) {
+	o := setupTest(t)
+	for v, releaseDate := range map[string]string{"1.30.9": "2024-12-01", "1.31.1": "2024-08-01", "1.31.2": "2024-09-19"} {
+		versionDir := filepath.Join(o.DataHome, "envoy-versions", v)
+		require.NoError(t, os.MkdirAll(versionDir, 0o700))
+		morerequire.RequireSetMtime(t, versionDir, releaseDate)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(o.ConfigHome, "envoy-version"), []byte("1.31"), 0o600))
+
+	c, stdout, stderr := newApp(o)
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "versions", "-o", "json"}))
+	require.Empty(t, stderr)
+
+	var infos []map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &infos))
+	current := map[string]any{}
+	for _, info := range infos {
+		current[info["version"].(string)] = info["current"]
+		if info["version"] == "1.31.2" {
+			require.Equal(t, "$FUNC_E_CONFIG_HOME/envoy-version", info["source"])
+			require.Equal(t, "1.31", info["selection"])
+		}
+	}
+	require.Equal(t, map[string]any{"1.30.9": false, "1.31.1": false, "1.31.2": true}, current)
+}
+
+func TestFuncEVersions_OutputEmpty(t *testing.T) {
+	c, stdout, _ := newApp(setupTest(t))
+	require.NoError(t, c.Run(t.Context(), []string{"func-e", "versions", "-o", "json"}))
+	require.Equal(t, "[]\n", stdout.String())
+}
+
+func TestFuncEVersions_InvalidOutput(t *testing.T) {
+	c, _, _ := newApp(setupTest(t))
+	err := c.Run(t.Context(), []string{"func-e", "versions", "-o", "xml"})
+	require.EqualError(t, err, `invalid --output "xml", should be "text", "json" or "yaml"`)
+}
+
 func setupTestVersions(t *testing.T) (o *globals.GlobalOpts) {
 	t.Helper()
 	o = setupTest(t)
@@ -163,3 +416,52 @@ func setupTestVersions(t *testing.T) (o *globals.GlobalOpts) {
 	morerequire.RequireSetMtime(t, oneTwoTwo, "2021-01-31")
 	return o
 }
+
+func TestFuncEVersionsValidate(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`{"versions":{"1.31.2":{"releaseDate":"2024/09/19",`+
+		`"tarballs":{"linux/amd64":"envoy-v1.31.2-linux-amd64.tar.xz"}}},"sha256sums":{}}`), 0o600))
+
+	tests := []struct {
+		name        string
+		arg         string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "url",
+			arg:      "http://" + admin.ServerAddr + "/envoy-versions.json",
+			expected: "http://" + admin.ServerAddr + "/envoy-versions.json: OK\n",
+		},
+		{
+			name: "file",
+			arg:  invalid,
+			expectedErr: "invalid Envoy versions JSON file://" + filepath.ToSlash(invalid) + ":\n" +
+				`versions["1.31.2"].releaseDate: invalid date "2024/09/19": should look like "2006-01-02"` + "\n" +
+				`versions["1.31.2"].tarballs["linux/amd64"]: missing sha256sums["envoy-v1.31.2-linux-amd64.tar.xz"]`,
+		},
+		{
+			name:        "missing",
+			expectedErr: "missing <url|file> argument",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c, stdout, _ := newApp(setupTest(t))
+			args := []string{"func-e", "versions", "validate"}
+			if tc.arg != "" {
+				args = append(args, tc.arg)
+			}
+
+			err := c.Run(t.Context(), args)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, stdout.String())
+		})
+	}
+}
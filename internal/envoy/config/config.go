@@ -6,14 +6,23 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type config struct {
-	Admin           *adminResource   `yaml:"admin"`
-	StaticResources *staticResources `yaml:"static_resources"`
+	Node             node              `yaml:"node"`
+	Admin            *adminResource    `yaml:"admin"`
+	StaticResources  *staticResources  `yaml:"static_resources"`
+	DynamicResources *dynamicResources `yaml:"dynamic_resources"`
+	LayeredRuntime   *layeredRuntime   `yaml:"layered_runtime"`
+}
+
+type node struct {
+	ID      string `yaml:"id"`
+	Cluster string `yaml:"cluster"`
 }
 
 type adminResource struct {
@@ -22,6 +31,7 @@ type adminResource struct {
 
 type staticResources struct {
 	Listeners []listener `yaml:"listeners"`
+	Clusters  []cluster  `yaml:"clusters"`
 }
 
 type listener struct {
@@ -40,6 +50,65 @@ type filter struct {
 	TypedConfig yaml.Node `yaml:"typed_config"`
 }
 
+type cluster struct {
+	Name            string          `yaml:"name"`
+	Type            string          `yaml:"type"`
+	ClusterType     *customCluster  `yaml:"cluster_type"`
+	LoadAssignment  *loadAssignment `yaml:"load_assignment"`
+	TransportSocket *filter         `yaml:"transport_socket"`
+}
+
+type customCluster struct {
+	Name string `yaml:"name"`
+}
+
+type loadAssignment struct {
+	Endpoints []struct {
+		LbEndpoints []struct {
+			Endpoint struct {
+				Address Address `yaml:"address"`
+			} `yaml:"endpoint"`
+		} `yaml:"lb_endpoints"`
+	} `yaml:"endpoints"`
+}
+
+type dynamicResources struct {
+	LdsConfig *configSource    `yaml:"lds_config"`
+	CdsConfig *configSource    `yaml:"cds_config"`
+	AdsConfig *apiConfigSource `yaml:"ads_config"`
+}
+
+type configSource struct {
+	Path             string           `yaml:"path"`
+	PathConfigSource *pathSource      `yaml:"path_config_source"`
+	APIConfigSource  *apiConfigSource `yaml:"api_config_source"`
+	Ads              *struct{}        `yaml:"ads"`
+}
+
+type pathSource struct {
+	Path string `yaml:"path"`
+}
+
+type apiConfigSource struct {
+	APIType      string   `yaml:"api_type"`
+	ClusterNames []string `yaml:"cluster_names"`
+	GrpcServices []struct {
+		EnvoyGrpc *struct {
+			ClusterName string `yaml:"cluster_name"`
+		} `yaml:"envoy_grpc"`
+	} `yaml:"grpc_services"`
+}
+
+type layeredRuntime struct {
+	Layers []struct {
+		Name        string    `yaml:"name"`
+		StaticLayer yaml.Node `yaml:"static_layer"`
+		DiskLayer   yaml.Node `yaml:"disk_layer"`
+		AdminLayer  yaml.Node `yaml:"admin_layer"`
+		RtdsLayer   yaml.Node `yaml:"rtds_layer"`
+	} `yaml:"layers"`
+}
+
 // Address is the YAML representation of an envoy.config.core.v3.Address.
 type Address struct {
 	SocketAddress socketAddress `yaml:"socket_address"`
@@ -57,7 +126,7 @@ type filterInfo struct {
 	Config string `yaml:"config"`
 }
 
-// Listener is a flattened view of a static listener parsed from bootstrap YAML.
+// Listener is a flattened view of a static listener parsed from the bootstrap.
 type Listener struct {
 	Name     string
 	Address  string // host:port format
@@ -65,65 +134,129 @@ type Listener struct {
 	Filters  []filterInfo
 }
 
-// Config holds the admin and static listener metadata parsed from bootstrap YAML.
+// Cluster is a flattened view of a static cluster parsed from the bootstrap.
+type Cluster struct {
+	Name      string
+	Type      string   // discovery type, such as STATIC or EDS, or the name of a custom cluster type
+	Endpoints []string // host:port format
+	TLS       bool     // true if the upstream transport socket is TLS
+}
+
+// Node is the identity Envoy presents to management servers.
+type Node struct {
+	ID      string
+	Cluster string
+}
+
+// DynamicResources are the xDS sources parsed from the bootstrap. Nil sources are not configured.
+type DynamicResources struct {
+	ADS *ConfigSource
+	LDS *ConfigSource
+	CDS *ConfigSource
+}
+
+// ConfigSource is a flattened view of where Envoy fetches a type of resource.
+type ConfigSource struct {
+	ADS      bool     // true if resources are fetched over the ADS stream
+	APIType  string   // such as GRPC or DELTA_GRPC, empty when not an API source
+	Clusters []string // names of the clusters the API is served from
+	Path     string   // filesystem path, empty when not a path source
+}
+
+// RuntimeLayer is a layer of the bootstrap layered_runtime.
+type RuntimeLayer struct {
+	Name string
+	Type string // static_layer, disk_layer, admin_layer or rtds_layer
+}
+
+// Config holds the metadata parsed from the bootstrap.
 type Config struct {
-	Admin           string // host:port format, empty if no admin
-	StaticListeners []Listener
+	Admin            string // host:port format, empty if no admin
+	Node             Node
+	StaticListeners  []Listener
+	StaticClusters   []Cluster
+	DynamicResources *DynamicResources // nil if no dynamic_resources
+	LayeredRuntime   []RuntimeLayer
 }
 
-// ParseListeners parses the admin address (if any) and all static listeners from config sources.
+// ParseListeners parses the admin address (if any), static listeners and
+// clusters, dynamic resources, node identity and runtime layers from config sources.
 //
 // This mimics Envoy's config merging behavior from source/server/server.cc:
 //   - configPath is loaded first (if non-empty)
 //   - configYaml is merged on top via protobuf MergeFrom (if non-empty)
 //   - configYaml always wins for conflicting fields, regardless of which was specified first on CLI
 //
-// For listeners with the same name, the later config wins (protobuf MergeFrom behavior).
+// For listeners, clusters or runtime layers with the same name, the later
+// config wins (protobuf MergeFrom behavior).
 func ParseListeners(configPath, configYaml string) (*Config, error) {
-	listenerMap := make(map[string]Listener)
-	var adminAddr string
+	result := &Config{StaticListeners: []Listener{}}
 
 	// Load config-path first
 	if configPath != "" {
-		yamlBytes, err := os.ReadFile(configPath) //nolint:gosec // configPath is the user-provided Envoy bootstrap path
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file %s: %w", configPath, err)
-		}
-		adminFromFile, listeners, err := parseListenersFromYAML(string(yamlBytes))
+		fromFile, err := parseFile(configPath)
 		if err != nil {
 			return nil, err
 		}
-		if adminFromFile != "" {
-			adminAddr = adminFromFile
-		}
-		for _, l := range listeners {
-			listenerMap[l.Name] = l
-		}
+		result.merge(fromFile)
 	}
 
 	// Merge config-yaml on top (always wins)
 	if configYaml != "" {
-		adminFromYaml, listeners, err := parseListenersFromYAML(configYaml)
+		fromYaml, err := parseYAML(configYaml)
 		if err != nil {
 			return nil, err
 		}
-		if adminFromYaml != "" {
-			adminAddr = adminFromYaml
+		result.merge(fromYaml)
+	}
+	return result, nil
+}
+
+// merge merges src on top of c, where set fields of src win.
+func (c *Config) merge(src *Config) {
+	if src.Admin != "" {
+		c.Admin = src.Admin
+	}
+	if src.Node.ID != "" {
+		c.Node.ID = src.Node.ID
+	}
+	if src.Node.Cluster != "" {
+		c.Node.Cluster = src.Node.Cluster
+	}
+	c.StaticListeners = mergeByName(c.StaticListeners, src.StaticListeners, func(l Listener) string { return l.Name })
+	c.StaticClusters = mergeByName(c.StaticClusters, src.StaticClusters, func(cl Cluster) string { return cl.Name })
+	c.LayeredRuntime = mergeByName(c.LayeredRuntime, src.LayeredRuntime, func(l RuntimeLayer) string { return l.Name })
+	if d := src.DynamicResources; d != nil {
+		if c.DynamicResources == nil {
+			c.DynamicResources = &DynamicResources{}
+		}
+		if d.ADS != nil {
+			c.DynamicResources.ADS = d.ADS
 		}
-		for _, l := range listeners {
-			listenerMap[l.Name] = l
+		if d.LDS != nil {
+			c.DynamicResources.LDS = d.LDS
+		}
+		if d.CDS != nil {
+			c.DynamicResources.CDS = d.CDS
 		}
 	}
+}
 
-	allListeners := make([]Listener, 0, len(listenerMap))
-	for _, l := range listenerMap {
-		allListeners = append(allListeners, l)
+// mergeByName appends src to dst, replacing any element of dst with the same name. Unnamed elements are always
+// appended, as Envoy doesn't consider them the same.
+func mergeByName[T any](dst, src []T, name func(T) string) []T {
+	for _, s := range src {
+		i := -1
+		if n := name(s); n != "" {
+			i = slices.IndexFunc(dst, func(d T) bool { return name(d) == n })
+		}
+		if i >= 0 {
+			dst[i] = s
+		} else {
+			dst = append(dst, s)
+		}
 	}
-
-	return &Config{
-		Admin:           adminAddr,
-		StaticListeners: allListeners,
-	}, nil
+	return dst
 }
 
 // FindAdminAddress parses the admin address from config sources.
@@ -165,18 +298,88 @@ func FindAdminAddressFromArgs(args []string) (string, error) {
 	return FindAdminAddress(configPath, configYaml)
 }
 
-func parseListenersFromYAML(yamlString string) (admin string, listeners []Listener, err error) {
-	cfg := config{StaticResources: &staticResources{}} // prevent nils
-	err = yaml.Unmarshal([]byte(yamlString), &cfg)
+// parseFile parses a --config-path file.
+func parseFile(configPath string) (*Config, error) {
+	n, _, err := loadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfig(n)
+}
+
+func parseYAML(yamlString string) (*Config, error) {
+	n, err := loadYAML(yamlString)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfig(n)
+}
+
+// loadFile loads a --config-path file in the format Envoy chooses by its
+// extension, in source/common/protobuf/utility.cc:
+//   - ".pb" is a binary Bootstrap proto
+//   - ".pb_text" is a text format Bootstrap proto
+//   - ".yaml" or ".yml" is YAML
+//   - otherwise, it is JSON, which is parsed as the YAML subset it is
+//
+// fromProto is true when the node was converted from a proto, so has no line numbers.
+func loadFile(configPath string) (n *yaml.Node, fromProto bool, err error) {
+	data, err := os.ReadFile(configPath) //nolint:gosec // configPath is the user-provided Envoy bootstrap path
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		return nil, false, fmt.Errorf("failed to read config file %s: %w", configPath, err)
+	}
+
+	var bootstrap map[string]any
+	switch {
+	case strings.HasSuffix(configPath, ".pb"):
+		if bootstrap, err = unmarshalProto(data, bootstrapFields); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal binary proto: %w", err)
+		}
+	case strings.HasSuffix(configPath, ".pb_text"):
+		if bootstrap, err = unmarshalProtoText(string(data), bootstrapFields); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal proto text: %w", err)
+		}
+	default:
+		n, err = loadYAML(string(data))
+		return n, false, err
+	}
+
+	// Convert the proto fields to YAML, so that all formats share the same
+	// field names and defaults.
+	n = &yaml.Node{}
+	if err = n.Encode(bootstrap); err != nil {
+		return nil, false, err
 	}
+	return n, true, nil
+}
+
+func loadYAML(yamlString string) (*yaml.Node, error) {
+	var n yaml.Node
+	if err := yaml.Unmarshal([]byte(yamlString), &n); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+	return &n, nil
+}
+
+func decodeConfig(n *yaml.Node) (*Config, error) {
+	cfg := config{StaticResources: &staticResources{}} // prevent nils
+	// An empty document has no node kind.
+	if n.Kind != 0 {
+		if err := n.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+		}
+	}
+	return parseConfig(&cfg), nil
+}
+
+func parseConfig(cfg *config) *Config {
+	result := &Config{Node: Node(cfg.Node)}
 
 	// Parse admin if present
 	if cfg.Admin != nil {
 		sa := cfg.Admin.Address.SocketAddress
 		if sa.Address != "" && sa.PortValue >= 0 {
-			admin = formatAddr(sa)
+			result.Admin = formatAddr(sa)
 		}
 	}
 
@@ -195,7 +398,7 @@ func parseListenersFromYAML(yamlString string) (admin string, listeners []Listen
 			filters = append(filters, extractFilterInfo(&l.ListenerFilters[j]))
 		}
 
-		listeners = append(listeners, Listener{
+		result.StaticListeners = append(result.StaticListeners, Listener{
 			Name:     l.Name,
 			Address:  formatAddr(l.Address.SocketAddress),
 			Protocol: l.Address.SocketAddress.Protocol,
@@ -203,7 +406,87 @@ func parseListenersFromYAML(yamlString string) (admin string, listeners []Listen
 		})
 	}
 
-	return admin, listeners, nil
+	for i := range cfg.StaticResources.Clusters {
+		result.StaticClusters = append(result.StaticClusters, extractCluster(&cfg.StaticResources.Clusters[i]))
+	}
+
+	if d := cfg.DynamicResources; d != nil {
+		result.DynamicResources = &DynamicResources{
+			ADS: extractAPIConfigSource(d.AdsConfig),
+			LDS: extractConfigSource(d.LdsConfig),
+			CDS: extractConfigSource(d.CdsConfig),
+		}
+	}
+
+	if cfg.LayeredRuntime != nil {
+		for _, l := range cfg.LayeredRuntime.Layers {
+			layer := RuntimeLayer{Name: l.Name}
+			switch {
+			case l.StaticLayer.Kind != 0:
+				layer.Type = "static_layer"
+			case l.DiskLayer.Kind != 0:
+				layer.Type = "disk_layer"
+			case l.AdminLayer.Kind != 0:
+				layer.Type = "admin_layer"
+			case l.RtdsLayer.Kind != 0:
+				layer.Type = "rtds_layer"
+			}
+			result.LayeredRuntime = append(result.LayeredRuntime, layer)
+		}
+	}
+	return result
+}
+
+// tlsTransportSocket is the transport socket name Envoy uses for TLS.
+const tlsTransportSocket = "envoy.transport_sockets.tls"
+
+func extractCluster(c *cluster) Cluster {
+	result := Cluster{Name: c.Name, Type: c.Type}
+	switch {
+	case c.ClusterType != nil:
+		result.Type = c.ClusterType.Name
+	case result.Type == "":
+		result.Type = "STATIC" // the default of envoy.config.cluster.v3.Cluster.DiscoveryType
+	}
+	if c.LoadAssignment != nil {
+		for _, e := range c.LoadAssignment.Endpoints {
+			for _, lb := range e.LbEndpoints {
+				result.Endpoints = append(result.Endpoints, formatAddr(lb.Endpoint.Address.SocketAddress))
+			}
+		}
+	}
+	if ts := c.TransportSocket; ts != nil {
+		result.TLS = ts.Name == tlsTransportSocket ||
+			strings.HasSuffix(extractFilterInfo(ts).Type, ".UpstreamTlsContext")
+	}
+	return result
+}
+
+func extractConfigSource(cs *configSource) *ConfigSource {
+	if cs == nil {
+		return nil
+	}
+	if cs.APIConfigSource != nil {
+		return extractAPIConfigSource(cs.APIConfigSource)
+	}
+	result := &ConfigSource{ADS: cs.Ads != nil, Path: cs.Path}
+	if cs.PathConfigSource != nil {
+		result.Path = cs.PathConfigSource.Path
+	}
+	return result
+}
+
+func extractAPIConfigSource(api *apiConfigSource) *ConfigSource {
+	if api == nil {
+		return nil
+	}
+	result := &ConfigSource{APIType: api.APIType, Clusters: api.ClusterNames}
+	for _, g := range api.GrpcServices {
+		if g.EnvoyGrpc != nil {
+			result.Clusters = append(result.Clusters, g.EnvoyGrpc.ClusterName)
+		}
+	}
+	return result
 }
 
 func extractFilterInfo(f *filter) filterInfo {
@@ -32,6 +32,62 @@ func TestParseListeners(t *testing.T) {
 	accessLogPath := filepath.Join(testdataDir, "access_log.yaml")
 	staticFilePath := filepath.Join(testdataDir, "static_file.yaml")
 	udpProxyPath := filepath.Join(testdataDir, "udp_proxy.yaml")
+	adminLocalhostJSONPath := filepath.Join(testdataDir, "admin_localhost.json")
+	adminLocalhostTextPath := filepath.Join(testdataDir, "admin_localhost.pb_text")
+	udpProxyTextPath := filepath.Join(testdataDir, "udp_proxy.pb_text")
+	xdsPath := filepath.Join(testdataDir, "xds.yaml")
+	xdsTextPath := filepath.Join(testdataDir, "xds.pb_text")
+
+	// Envoy chooses the format by extension, so the same text isn't parsed as YAML.
+	adminLocalhostYAMLAsTextPath := filepath.Join(t.TempDir(), "admin_localhost.pb_text")
+	yamlBytes, err := os.ReadFile(adminLocalhostPath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(adminLocalhostYAMLAsTextPath, yamlBytes, 0o600))
+
+	adminLocalhostBinaryPath := filepath.Join(t.TempDir(), "admin_localhost.pb")
+	require.NoError(t, os.WriteFile(adminLocalhostBinaryPath, concat(
+		protoMessage(12, protoMessage(3, protoMessage(1, protoString(2, "127.0.0.1"), protoVarint(3, 9901)))),
+		protoMessage(2, protoMessage(1,
+			protoString(1, "test_listener"),
+			protoMessage(2, protoMessage(1, protoString(2, "0.0.0.0"), protoVarint(3, 10000))),
+		)),
+	), 0o600))
+	invalidBinaryPath := filepath.Join(t.TempDir(), "invalid.pb")
+	require.NoError(t, os.WriteFile(invalidBinaryPath, []byte{0x12, 0x05}, 0o600))
+
+	xdsConfig := func() *Config {
+		return &Config{
+			Node:            Node{ID: "test-id", Cluster: "test-cluster"},
+			StaticListeners: []Listener{},
+			StaticClusters: []Cluster{
+				{
+					Name:      "xds_cluster",
+					Type:      "STRICT_DNS",
+					Endpoints: []string{"xds.example.com:18000"},
+					TLS:       true,
+				},
+				{
+					Name:      "local_service",
+					Type:      "STATIC",
+					Endpoints: []string{"127.0.0.1:8080", "127.0.0.1:8081"},
+				},
+			},
+			DynamicResources: &DynamicResources{
+				ADS: &ConfigSource{APIType: "GRPC", Clusters: []string{"xds_cluster"}},
+				LDS: &ConfigSource{ADS: true},
+				CDS: &ConfigSource{Path: "/etc/envoy/cds.yaml"},
+			},
+			LayeredRuntime: []RuntimeLayer{
+				{Name: "static", Type: "static_layer"},
+				{Name: "admin", Type: "admin_layer"},
+			},
+		}
+	}
+	xdsMerged := xdsConfig()
+	xdsMerged.Node.ID = "override-id"
+	xdsMerged.StaticClusters[1] = Cluster{Name: "local_service", Type: "EDS"}
+	xdsMerged.StaticClusters = append(xdsMerged.StaticClusters, Cluster{Name: "extra", Type: "envoy.clusters.aggregate"})
+	xdsMerged.DynamicResources.CDS = &ConfigSource{ADS: true}
 
 	tests := []struct {
 		name        string
@@ -127,6 +183,96 @@ http_filters:
 				}},
 			},
 		},
+		{
+			name:       "admin_localhost_json",
+			configPath: adminLocalhostJSONPath,
+			expected: &Config{
+				Admin: admin.ServerAddr,
+				StaticListeners: []Listener{{
+					Name:    "test_listener",
+					Address: "0.0.0.0:10000",
+				}},
+			},
+		},
+		{
+			name:       "admin_localhost_pb_text",
+			configPath: adminLocalhostTextPath,
+			expected: &Config{
+				Admin: admin.ServerAddr,
+				StaticListeners: []Listener{{
+					Name:    "test_listener",
+					Address: "0.0.0.0:10000",
+				}},
+			},
+		},
+		{
+			name:       "admin_localhost_pb",
+			configPath: adminLocalhostBinaryPath,
+			expected: &Config{
+				Admin: admin.ServerAddr,
+				StaticListeners: []Listener{{
+					Name:    "test_listener",
+					Address: "0.0.0.0:10000",
+				}},
+			},
+		},
+		{
+			name:       "udp_proxy_pb_text",
+			configPath: udpProxyTextPath,
+			expected: &Config{
+				Admin: "",
+				StaticListeners: []Listener{{
+					Name:     "udp_listener",
+					Address:  "127.0.0.1:10000",
+					Protocol: "UDP",
+					Filters: []filterInfo{{
+						Name: "envoy.filters.udp_listener.udp_proxy",
+						Type: "type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.UdpProxyConfig",
+						Config: `'@type': type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.UdpProxyConfig
+matcher:
+    on_no_match:
+        action:
+            name: route
+            typed_config:
+                '@type': type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.Route
+                cluster: cluster_0
+stat_prefix: foo
+`,
+					}},
+				}},
+			},
+		},
+		{
+			name:       "xds",
+			configPath: xdsPath,
+			expected:   xdsConfig(),
+		},
+		{
+			name:       "xds_pb_text",
+			configPath: xdsTextPath,
+			expected:   xdsConfig(),
+		},
+		{
+			name:       "xds_merged_by_name",
+			configPath: xdsPath,
+			configYaml: `node: {id: override-id}
+dynamic_resources: {cds_config: {ads: {}}}
+static_resources:
+  clusters:
+    - {name: local_service, type: EDS}
+    - {name: extra, cluster_type: {name: envoy.clusters.aggregate}}`,
+			expected: xdsMerged,
+		},
+		{
+			name:        "yaml_in_pb_text",
+			configPath:  adminLocalhostYAMLAsTextPath,
+			expectedErr: "failed to unmarshal proto text: line 2:10: invalid value \"address\" for field admin",
+		},
+		{
+			name:        "invalid_pb",
+			configPath:  invalidBinaryPath,
+			expectedErr: "failed to unmarshal binary proto: unexpected end of data",
+		},
 		{
 			name:        "invalid_yaml",
 			configYaml:  "invalid: {yaml",
@@ -156,6 +302,19 @@ http_filters:
 				}},
 			},
 		},
+		{
+			name: "unnamed listeners aren't merged",
+			configYaml: `static_resources:
+  listeners:
+    - {address: {socket_address: {address: 127.0.0.1, port_value: 10000}}}
+    - {address: {socket_address: {address: 127.0.0.1, port_value: 10001}}}`,
+			expected: &Config{
+				StaticListeners: []Listener{
+					{Address: "127.0.0.1:10000"},
+					{Address: "127.0.0.1:10001"},
+				},
+			},
+		},
 		{
 			name:       "udp_proxy",
 			configPath: udpProxyPath,
@@ -215,6 +374,11 @@ func TestFindAdminAddress(t *testing.T) {
 			configPath: adminLocalhostPath,
 			expected:   admin.ServerAddr,
 		},
+		{
+			name:       "proto_text_file_with_admin",
+			configPath: filepath.Join(testdataDir, "admin_localhost.pb_text"),
+			expected:   admin.ServerAddr,
+		},
 		{
 			name:       "file_without_admin",
 			configPath: noAdminPath,
@@ -0,0 +1,289 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigYamlFile is the Finding.File of problems in --config-yaml.
+const ConfigYamlFile = "--config-yaml"
+
+// Names of the checks Lint performs.
+const (
+	CheckDuplicateAddress    = "duplicate-address"
+	CheckOverwrittenListener = "overwritten-listener"
+	CheckPortInUse           = "port-in-use"
+	CheckAdminNotLoopback    = "admin-not-loopback"
+	CheckMissingType         = "missing-type"
+)
+
+// Finding is a likely mistake in the bootstrap, found before Envoy starts.
+type Finding struct {
+	// File is the config path, or ConfigYamlFile.
+	File string `json:"file"`
+	// Line and Column are the position in File, or zero when it is a proto.
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+	Check  string `json:"check"`
+	// Message describes the problem.
+	Message string `json:"message"`
+}
+
+// String returns the finding like "envoy.yaml:12:9: message (check)".
+func (f *Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s (%s)", f.File, f.Message, f.Check)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", f.File, f.Line, f.Column, f.Message, f.Check)
+}
+
+// lintListener is a static listener, with the position of each part the checks report on.
+type lintListener struct {
+	Listener
+	at, addressAt position
+	// missingType are the positions of filters without an "@type".
+	missingType []position
+}
+
+type position struct {
+	file         string
+	line, column int
+}
+
+func (p position) finding(check, format string, args ...any) Finding {
+	return Finding{File: p.file, Line: p.line, Column: p.column, Check: check, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p position) String() string {
+	if p.line == 0 {
+		return p.file
+	}
+	return fmt.Sprintf("%s:%d", p.file, p.line)
+}
+
+// Lint reports common mistakes in the bootstrap from config sources, which
+// are merged the same way as ParseListeners. Findings are in file order, with
+// --config-yaml last.
+func Lint(configPath, configYaml string) ([]Finding, error) {
+	var findings []Finding
+	var listeners []*lintListener
+	var admin string
+	var adminAt position
+
+	merge := func(file string, n *yaml.Node, fromProto bool) {
+		if fromProto { // line numbers are meaningless
+			clearPositions(n)
+		}
+		root := n
+		if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+			root = root.Content[0]
+		}
+		if a := lookup(root, "admin", "address", "socket_address"); a != nil {
+			var sa socketAddress
+			if err := a.Decode(&sa); err == nil && sa.Address != "" && sa.PortValue >= 0 {
+				admin, adminAt = formatAddr(sa), positionOf(file, a)
+			}
+		}
+		for _, l := range lintListeners(file, lookup(root, "static_resources", "listeners")) {
+			i := indexOfListener(listeners, l.Name)
+			if i < 0 {
+				listeners = append(listeners, l)
+				continue
+			}
+			if file == ConfigYamlFile {
+				findings = append(findings, l.at.finding(CheckOverwrittenListener,
+					"listener %q replaces the one at %s", l.Name, listeners[i].at))
+			}
+			listeners[i] = l
+		}
+	}
+
+	if configPath != "" {
+		n, fromProto, err := loadFile(configPath)
+		if err != nil {
+			return nil, err
+		}
+		merge(configPath, n, fromProto)
+	}
+	if configYaml != "" {
+		n, err := loadYAML(configYaml)
+		if err != nil {
+			return nil, err
+		}
+		merge(ConfigYamlFile, n, false)
+	}
+
+	bound := map[string]*lintListener{}
+	for _, l := range listeners {
+		for _, at := range l.missingType {
+			findings = append(findings, at.finding(CheckMissingType, "filter of listener %q has no typed_config \"@type\"", l.Name))
+		}
+		network := listenerNetwork(l.Protocol)
+		if isEphemeral(l.Address) {
+			continue
+		}
+		if other, ok := bound[network+" "+l.Address]; ok {
+			findings = append(findings, l.addressAt.finding(CheckDuplicateAddress,
+				"listener %q is bound to %s, the same as listener %q at %s", l.Name, l.Address, other.Name, other.at))
+			continue
+		}
+		bound[network+" "+l.Address] = l
+		if err := checkBind(network, l.Address); err != nil {
+			findings = append(findings, l.addressAt.finding(CheckPortInUse, "listener %q can't bind %s: %v", l.Name, l.Address, err))
+		}
+	}
+
+	if admin != "" {
+		if host, _, err := net.SplitHostPort(admin); err == nil && !isLoopback(host) {
+			findings = append(findings, adminAt.finding(CheckAdminNotLoopback,
+				"admin address %s is reachable from other hosts, but the admin API can change Envoy", admin))
+		}
+		if _, ok := bound["tcp "+admin]; !ok && !isEphemeral(admin) {
+			if err := checkBind("tcp", admin); err != nil {
+				findings = append(findings, adminAt.finding(CheckPortInUse, "admin can't bind %s: %v", admin, err))
+			}
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		fi, fj := findings[i], findings[j]
+		if fi.File != fj.File {
+			return fj.File == ConfigYamlFile
+		}
+		return fi.Line < fj.Line || fi.Line == fj.Line && fi.Column < fj.Column
+	})
+	return findings, nil
+}
+
+// lintListeners decodes the listeners sequence node, keeping the position of each part.
+func lintListeners(file string, seq *yaml.Node) (listeners []*lintListener) {
+	if seq == nil || seq.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, n := range seq.Content {
+		var l listener
+		if err := n.Decode(&l); err != nil {
+			continue // Envoy will report the type error
+		}
+		cfg := config{StaticResources: &staticResources{Listeners: []listener{l}}}
+		ll := &lintListener{Listener: parseConfig(&cfg).StaticListeners[0], at: positionOf(file, n), addressAt: positionOf(file, n)}
+		if a := lookup(n, "address", "socket_address"); a != nil {
+			ll.addressAt = positionOf(file, a)
+		}
+
+		var filters []*yaml.Node
+		if chains := lookup(n, "filter_chains"); chains != nil && chains.Kind == yaml.SequenceNode {
+			for _, chain := range chains.Content {
+				if fs := lookup(chain, "filters"); fs != nil && fs.Kind == yaml.SequenceNode {
+					filters = append(filters, fs.Content...)
+				}
+			}
+		}
+		if fs := lookup(n, "listener_filters"); fs != nil && fs.Kind == yaml.SequenceNode {
+			filters = append(filters, fs.Content...)
+		}
+		for _, f := range filters {
+			tc := lookup(f, "typed_config")
+			switch {
+			case tc == nil:
+				ll.missingType = append(ll.missingType, positionOf(file, f))
+			case lookup(tc, "@type") == nil:
+				ll.missingType = append(ll.missingType, positionOf(file, tc))
+			}
+		}
+		listeners = append(listeners, ll)
+	}
+	return listeners
+}
+
+// lookup returns the node at the path of mapping keys, or nil if any are missing.
+func lookup(n *yaml.Node, path ...string) *yaml.Node {
+	for _, key := range path {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				next = n.Content[i+1]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+func positionOf(file string, n *yaml.Node) position {
+	return position{file: file, line: n.Line, column: n.Column}
+}
+
+func clearPositions(n *yaml.Node) {
+	n.Line, n.Column = 0, 0
+	for _, c := range n.Content {
+		clearPositions(c)
+	}
+}
+
+// indexOfListener returns the index of the listener with the name, or -1. Unnamed listeners are never the same one.
+func indexOfListener(listeners []*lintListener, name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, l := range listeners {
+		if l.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func listenerNetwork(protocol string) string {
+	if protocol == "UDP" {
+		return "udp"
+	}
+	return "tcp"
+}
+
+// isEphemeral returns true if the address has port zero, so can't conflict.
+func isEphemeral(addr string) bool {
+	_, port, err := net.SplitHostPort(addr)
+	return err != nil || port == "0"
+}
+
+// checkBind returns an error if the address can't be bound, such as when it is in use.
+func checkBind(network, addr string) error {
+	var err error
+	if network == "udp" {
+		var c net.PacketConn
+		if c, err = net.ListenPacket(network, addr); err == nil {
+			return c.Close()
+		}
+	} else {
+		var l net.Listener
+		if l, err = net.Listen(network, addr); err == nil {
+			return l.Close()
+		}
+	}
+	if opErr, ok := errors.AsType[*net.OpError](err); ok {
+		return opErr.Err // e.g. "bind: address already in use", without the address
+	}
+	return err
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
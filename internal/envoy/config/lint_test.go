@@ -0,0 +1,114 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	testdataDir := testDataPath(t)
+	lintPath := filepath.Join(testdataDir, "lint.yaml")
+
+	inUse, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer inUse.Close()
+	inUsePort := inUse.Addr().(*net.TCPAddr).Port
+	inUseErr := checkBind("tcp", inUse.Addr().String()) // differs by OS
+	require.Error(t, inUseErr)
+
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	freePort := free.Addr().(*net.TCPAddr).Port
+	require.NoError(t, free.Close())
+
+	adminTextPath := filepath.Join(t.TempDir(), "admin.pb_text")
+	require.NoError(t, os.WriteFile(adminTextPath,
+		[]byte(`admin { address { socket_address { address: "0.0.0.0" port_value: 0 } } }`), 0o600))
+
+	tests := []struct {
+		name       string
+		configPath string
+		configYaml string
+		expected   []Finding
+	}{
+		{
+			name:       "no findings",
+			configPath: filepath.Join(testdataDir, "static_file.yaml"),
+		},
+		{
+			name:       "admin and missing types",
+			configPath: lintPath,
+			expected: []Finding{
+				{File: lintPath, Line: 4, Column: 7, Check: CheckAdminNotLoopback, Message: "admin address 0.0.0.0:0 is reachable from other hosts, but the admin API can change Envoy"},
+				{File: lintPath, Line: 14, Column: 11, Check: CheckMissingType, Message: `filter of listener "main" has no typed_config "@type"`},
+				{File: lintPath, Line: 19, Column: 17, Check: CheckMissingType, Message: `filter of listener "main" has no typed_config "@type"`},
+			},
+		},
+		{
+			name:       "listener overwritten by config yaml",
+			configPath: lintPath,
+			configYaml: `static_resources: {listeners: [{name: main, address: {socket_address: {address: 127.0.0.1, port_value: 0}}}]}`,
+			expected: []Finding{
+				{File: lintPath, Line: 4, Column: 7, Check: CheckAdminNotLoopback, Message: "admin address 0.0.0.0:0 is reachable from other hosts, but the admin API can change Envoy"},
+				{File: ConfigYamlFile, Line: 1, Column: 32, Check: CheckOverwrittenListener, Message: fmt.Sprintf(`listener "main" replaces the one at %s:8`, lintPath)},
+			},
+		},
+		{
+			name: "duplicate address in use",
+			configYaml: fmt.Sprintf(`static_resources:
+  listeners:
+    - {name: a, address: {socket_address: {address: 127.0.0.1, port_value: %[1]d}}}
+    - {name: b, address: {socket_address: {address: 127.0.0.1, port_value: %[1]d}}}
+    - {name: c, address: {socket_address: {address: 127.0.0.1, port_value: %[1]d, protocol: UDP}}}`, inUsePort),
+			expected: []Finding{
+				{File: ConfigYamlFile, Line: 3, Column: 43, Check: CheckPortInUse, Message: fmt.Sprintf(`listener "a" can't bind 127.0.0.1:%d: %v`, inUsePort, inUseErr)},
+				{File: ConfigYamlFile, Line: 4, Column: 43, Check: CheckDuplicateAddress, Message: fmt.Sprintf(`listener "b" is bound to 127.0.0.1:%d, the same as listener "a" at --config-yaml:3`, inUsePort)},
+			},
+		},
+		{
+			name: "unnamed listeners aren't merged",
+			configYaml: fmt.Sprintf(`static_resources:
+  listeners:
+    - {address: {socket_address: {address: 127.0.0.1, port_value: %[1]d}}}
+    - {address: {socket_address: {address: 127.0.0.1, port_value: %[1]d}}}`, freePort),
+			expected: []Finding{
+				{File: ConfigYamlFile, Line: 4, Column: 34, Check: CheckDuplicateAddress, Message: fmt.Sprintf(`listener "" is bound to 127.0.0.1:%d, the same as listener "" at --config-yaml:3`, freePort)},
+			},
+		},
+		{
+			name:       "proto has no position",
+			configPath: adminTextPath,
+			expected: []Finding{
+				{File: adminTextPath, Check: CheckAdminNotLoopback, Message: "admin address 0.0.0.0:0 is reachable from other hosts, but the admin API can change Envoy"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := Lint(tt.configPath, tt.configYaml)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, findings)
+		})
+	}
+}
+
+func TestLint_Error(t *testing.T) {
+	_, err := Lint("", "invalid: {yaml")
+	require.EqualError(t, err, "failed to unmarshal YAML: yaml: line 1: did not find expected ',' or '}'")
+}
+
+func TestFinding_String(t *testing.T) {
+	f := Finding{File: "envoy.yaml", Line: 12, Column: 9, Check: CheckPortInUse, Message: "in use"}
+	require.Equal(t, "envoy.yaml:12:9: in use (port-in-use)", f.String())
+	f.Line, f.Column = 0, 0
+	require.Equal(t, "envoy.yaml: in use (port-in-use)", f.String())
+}
@@ -0,0 +1,257 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// fieldKind is how a protobuf field value maps to its YAML equivalent.
+type fieldKind int
+
+const (
+	kindMessage fieldKind = iota
+	kindString
+	kindUint
+	// kindEnum is an enum, which maps to the name of its value.
+	kindEnum
+	// kindAny is a google.protobuf.Any, which maps to a YAML map with an "@type" key.
+	kindAny
+)
+
+// field is a protobuf field of the Bootstrap subset func-e reads. There is no
+// protobuf dependency, so the schema is hand-written from the Envoy API.
+type field struct {
+	name     string
+	kind     fieldKind
+	repeated bool
+	// fields are the known fields of a kindMessage, by field number.
+	fields map[uint64]*field
+	// enum are the value names of a kindEnum, indexed by number.
+	enum []string
+}
+
+var (
+	// socketAddressFields are from envoy.config.core.v3.SocketAddress.
+	socketAddressFields = map[uint64]*field{
+		1: {name: "protocol", kind: kindEnum, enum: []string{"TCP", "UDP"}},
+		2: {name: "address", kind: kindString},
+		3: {name: "port_value", kind: kindUint},
+	}
+	// addressFields are from envoy.config.core.v3.Address.
+	addressFields = map[uint64]*field{
+		1: {name: "socket_address", fields: socketAddressFields},
+	}
+	// filterFields are from envoy.config.listener.v3.Filter.
+	filterFields = map[uint64]*field{
+		1: {name: "name", kind: kindString},
+		4: {name: "typed_config", kind: kindAny},
+	}
+	// listenerFilterFields are from envoy.config.listener.v3.ListenerFilter.
+	listenerFilterFields = map[uint64]*field{
+		1: {name: "name", kind: kindString},
+		3: {name: "typed_config", kind: kindAny},
+	}
+	// listenerFields are from envoy.config.listener.v3.Listener.
+	listenerFields = map[uint64]*field{
+		1: {name: "name", kind: kindString},
+		2: {name: "address", fields: addressFields},
+		3: {name: "filter_chains", repeated: true, fields: map[uint64]*field{
+			3: {name: "filters", repeated: true, fields: filterFields},
+		}},
+		9: {name: "listener_filters", repeated: true, fields: listenerFilterFields},
+	}
+	// clusterFields are from envoy.config.cluster.v3.Cluster.
+	clusterFields = map[uint64]*field{
+		1: {name: "name", kind: kindString},
+		2: {name: "type", kind: kindEnum, enum: []string{"STATIC", "STRICT_DNS", "LOGICAL_DNS", "EDS", "ORIGINAL_DST"}},
+		24: {name: "transport_socket", fields: map[uint64]*field{
+			1: {name: "name", kind: kindString},
+			3: {name: "typed_config", kind: kindAny},
+		}},
+		// load_assignment is an envoy.config.endpoint.v3.ClusterLoadAssignment.
+		33: {name: "load_assignment", fields: map[uint64]*field{
+			2: {name: "endpoints", repeated: true, fields: map[uint64]*field{
+				2: {name: "lb_endpoints", repeated: true, fields: map[uint64]*field{
+					1: {name: "endpoint", fields: map[uint64]*field{
+						1: {name: "address", fields: addressFields},
+					}},
+				}},
+			}},
+		}},
+		38: {name: "cluster_type", fields: map[uint64]*field{
+			1: {name: "name", kind: kindString},
+		}},
+	}
+	// configSourceFields are from envoy.config.core.v3.ConfigSource.
+	configSourceFields = map[uint64]*field{
+		1: {name: "path", kind: kindString},
+		2: {name: "api_config_source", fields: apiConfigSourceFields},
+		3: {name: "ads", fields: map[uint64]*field{}},
+		8: {name: "path_config_source", fields: map[uint64]*field{
+			1: {name: "path", kind: kindString},
+		}},
+	}
+	// apiConfigSourceFields are from envoy.config.core.v3.ApiConfigSource.
+	apiConfigSourceFields = map[uint64]*field{
+		1: {name: "api_type", kind: kindEnum, enum: []string{
+			"DEPRECATED_AND_UNAVAILABLE_DO_NOT_USE", "REST", "GRPC", "DELTA_GRPC", "", "AGGREGATED_GRPC", "AGGREGATED_DELTA_GRPC",
+		}},
+		2: {name: "cluster_names", kind: kindString, repeated: true},
+		// grpc_services are envoy.config.core.v3.GrpcService.
+		4: {name: "grpc_services", repeated: true, fields: map[uint64]*field{
+			1: {name: "envoy_grpc", fields: map[uint64]*field{
+				1: {name: "cluster_name", kind: kindString},
+			}},
+		}},
+	}
+	// bootstrapFields are from envoy.config.bootstrap.v3.Bootstrap.
+	bootstrapFields = map[uint64]*field{
+		// node is an envoy.config.core.v3.Node.
+		1: {name: "node", fields: map[uint64]*field{
+			1: {name: "id", kind: kindString},
+			2: {name: "cluster", kind: kindString},
+		}},
+		2: {name: "static_resources", fields: map[uint64]*field{
+			1: {name: "listeners", repeated: true, fields: listenerFields},
+			2: {name: "clusters", repeated: true, fields: clusterFields},
+		}},
+		3: {name: "dynamic_resources", fields: map[uint64]*field{
+			1: {name: "lds_config", fields: configSourceFields},
+			2: {name: "cds_config", fields: configSourceFields},
+			3: {name: "ads_config", fields: apiConfigSourceFields},
+		}},
+		12: {name: "admin", fields: map[uint64]*field{
+			3: {name: "address", fields: addressFields},
+		}},
+		// layered_runtime is an envoy.config.bootstrap.v3.LayeredRuntime.
+		17: {name: "layered_runtime", fields: map[uint64]*field{
+			1: {name: "layers", repeated: true, fields: map[uint64]*field{
+				1: {name: "name", kind: kindString},
+				// The layer contents are not read, only which kind of layer it is.
+				2: {name: "static_layer", fields: map[uint64]*field{}},
+				3: {name: "disk_layer", fields: map[uint64]*field{}},
+				4: {name: "admin_layer", fields: map[uint64]*field{}},
+				5: {name: "rtds_layer", fields: map[uint64]*field{}},
+			}},
+		}},
+	}
+)
+
+// protobuf wire types, from https://protobuf.dev/programming-guides/encoding/
+const (
+	wireVarint = 0
+	wireI64    = 1
+	wireLen    = 2
+	wireI32    = 5
+)
+
+var errTruncated = errors.New("unexpected end of data")
+
+// unmarshalProto decodes the known fields of a binary protobuf message into
+// the same shape yaml.Unmarshal would produce for its YAML form. Unknown
+// fields are skipped.
+func unmarshalProto(data []byte, fields map[uint64]*field) (map[string]any, error) {
+	m := map[string]any{}
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		data = data[n:]
+		num, wireType := tag>>3, tag&7
+
+		var varint uint64
+		var bytes []byte
+		switch wireType {
+		case wireVarint:
+			if varint, n = binary.Uvarint(data); n <= 0 {
+				return nil, errTruncated
+			}
+			data = data[n:]
+		case wireI64, wireI32:
+			size := 8
+			if wireType == wireI32 {
+				size = 4
+			}
+			if len(data) < size {
+				return nil, errTruncated
+			}
+			data = data[size:]
+			continue // no known field is fixed width
+		case wireLen:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return nil, errTruncated
+			}
+			bytes, data = data[n:n+int(length)], data[n+int(length):]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d of field %d", wireType, num)
+		}
+
+		f, ok := fields[num]
+		if !ok {
+			continue
+		}
+		var v any
+		switch {
+		case f.kind == kindUint && wireType == wireVarint:
+			if varint > math.MaxInt64 {
+				return nil, fmt.Errorf("field %s overflows", f.name)
+			}
+			v = int64(varint)
+		case f.kind == kindEnum && wireType == wireVarint:
+			v = f.enumName(varint)
+		case f.kind == kindString && wireType == wireLen:
+			v = string(bytes)
+		case f.kind == kindMessage && wireType == wireLen:
+			msg, err := unmarshalProto(bytes, f.fields)
+			if err != nil {
+				return nil, err
+			}
+			v = msg
+		case f.kind == kindAny && wireType == wireLen:
+			// The value of an Any can't be decoded without its schema, so only the type is kept.
+			msg, err := unmarshalProto(bytes, map[uint64]*field{1: {name: "@type", kind: kindString}})
+			if err != nil {
+				return nil, err
+			}
+			v = msg
+		default:
+			return nil, fmt.Errorf("field %s has unexpected wire type %d", f.name, wireType)
+		}
+		setField(m, f, v)
+	}
+	return m, nil
+}
+
+// setField sets a field value with protobuf merge semantics: repeated fields
+// append, messages merge and scalars replace.
+func setField(m map[string]any, f *field, v any) {
+	if f.repeated {
+		list, _ := m[f.name].([]any)
+		m[f.name] = append(list, v)
+		return
+	}
+	if existing, ok := m[f.name].(map[string]any); ok {
+		if msg, ok := v.(map[string]any); ok {
+			for k, fv := range msg {
+				existing[k] = fv
+			}
+			return
+		}
+	}
+	m[f.name] = v
+}
+
+// enumName returns the name of an enum value, or its number when unknown.
+func (f *field) enumName(v uint64) string {
+	if v < uint64(len(f.enum)) && f.enum[v] != "" {
+		return f.enum[v]
+	}
+	return fmt.Sprint(v)
+}
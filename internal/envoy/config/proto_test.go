@@ -0,0 +1,137 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalProto(t *testing.T) {
+	socketAddress := protoMessage(1,
+		protoVarint(1, 1), // UDP
+		protoString(2, "127.0.0.1"),
+		protoVarint(3, 10000),
+	)
+	listener := protoMessage(1,
+		protoString(1, "udp_listener"),
+		protoMessage(2, socketAddress),
+		protoMessage(9,
+			protoString(1, "envoy.filters.udp_listener.udp_proxy"),
+			protoMessage(3,
+				protoString(1, "type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.UdpProxyConfig"),
+				protoString(2, "\x0a\x03foo"), // stat_prefix, which can't be decoded without its schema
+			),
+		),
+		protoFixed64(12), // unknown
+	)
+
+	actual, err := unmarshalProto(concat(
+		protoVarint(100, 1), // unknown
+		protoMessage(2, listener),
+		protoMessage(12, protoMessage(3, protoMessage(1, protoString(2, "127.0.0.1")))),
+		protoMessage(12, protoMessage(3, protoMessage(1, protoVarint(3, 9901)))), // merged
+	), bootstrapFields)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"static_resources": map[string]any{
+			"listeners": []any{map[string]any{
+				"name": "udp_listener",
+				"address": map[string]any{"socket_address": map[string]any{
+					"protocol":   "UDP",
+					"address":    "127.0.0.1",
+					"port_value": int64(10000),
+				}},
+				"listener_filters": []any{map[string]any{
+					"name": "envoy.filters.udp_listener.udp_proxy",
+					"typed_config": map[string]any{
+						"@type": "type.googleapis.com/envoy.extensions.filters.udp.udp_proxy.v3.UdpProxyConfig",
+					},
+				}},
+			}},
+		},
+		"admin": map[string]any{"address": map[string]any{"socket_address": map[string]any{
+			"port_value": int64(9901),
+		}}},
+	}, actual)
+}
+
+func TestUnmarshalProto_Enum(t *testing.T) {
+	actual, err := unmarshalProto(protoMessage(2,
+		protoMessage(2, protoString(1, "eds"), protoVarint(2, 3)),
+		protoMessage(2, protoString(1, "unknown"), protoVarint(2, 9)),
+	), bootstrapFields)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"static_resources": map[string]any{"clusters": []any{
+		map[string]any{"name": "eds", "type": "EDS"},
+		map[string]any{"name": "unknown", "type": "9"},
+	}}}, actual)
+}
+
+func TestUnmarshalProto_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		expectedErr string
+	}{
+		{
+			name:        "truncated length",
+			data:        protoMessage(2, protoString(1, "listener"))[:4],
+			expectedErr: "unexpected end of data",
+		},
+		{
+			name:        "truncated varint",
+			data:        []byte{0x08, 0x80},
+			expectedErr: "unexpected end of data",
+		},
+		{
+			name:        "group",
+			data:        []byte{0x0b},
+			expectedErr: "unsupported wire type 3 of field 1",
+		},
+		{
+			name:        "wrong wire type",
+			data:        protoVarint(2, 1),
+			expectedErr: "field static_resources has unexpected wire type 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unmarshalProto(tt.data, bootstrapFields)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func protoTag(num, wireType uint64) []byte {
+	return binary.AppendUvarint(nil, num<<3|wireType)
+}
+
+func protoVarint(num, v uint64) []byte {
+	return binary.AppendUvarint(protoTag(num, wireVarint), v)
+}
+
+func protoFixed64(num uint64) []byte {
+	return append(protoTag(num, wireI64), make([]byte, 8)...)
+}
+
+func protoString(num uint64, s string) []byte {
+	b := binary.AppendUvarint(protoTag(num, wireLen), uint64(len(s)))
+	return append(b, s...)
+}
+
+func protoMessage(num uint64, fields ...[]byte) []byte {
+	return protoString(num, string(concat(fields...)))
+}
+
+func concat(fields ...[]byte) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = append(b, f...)
+	}
+	return b
+}
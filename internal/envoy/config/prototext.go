@@ -0,0 +1,393 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// unmarshalProtoText decodes the known fields of a text format protobuf
+// message into the same shape yaml.Unmarshal would produce for its YAML form.
+// Unknown fields are parsed, but skipped.
+//
+// See https://protobuf.dev/reference/protobuf/textformat-spec/
+func unmarshalProtoText(s string, fields map[uint64]*field) (map[string]any, error) {
+	p := &textParser{s: s}
+	m, err := p.parseMessage(fields, 0)
+	if err != nil {
+		line := strings.Count(s[:p.pos], "\n") + 1
+		col := p.pos - strings.LastIndexByte(s[:p.pos], '\n')
+		return nil, fmt.Errorf("line %d:%d: %w", line, col, err)
+	}
+	return m, nil
+}
+
+type textParser struct {
+	s   string
+	pos int
+}
+
+// parseMessage parses fields until the end byte, or the end of input when zero.
+// When fields is nil, the message is unknown and all fields are kept as-is.
+func (p *textParser) parseMessage(fields map[uint64]*field, end byte) (map[string]any, error) {
+	m := map[string]any{}
+	for {
+		p.skipSpace()
+		if p.pos == len(p.s) {
+			if end != 0 {
+				return nil, fmt.Errorf("expected %q", end)
+			}
+			return m, nil
+		}
+		if p.s[p.pos] == end {
+			p.pos++
+			if fields == nil {
+				return expandAny(m), nil
+			}
+			return m, nil
+		}
+
+		name, err := p.parseFieldName()
+		if err != nil {
+			return nil, err
+		}
+		var f *field
+		if fields != nil {
+			f = lookupField(fields, name)
+		}
+		// A field unknown to a known message is parsed, but not kept.
+		discard := fields != nil && f == nil
+
+		p.skipSpace()
+		hasColon := p.consume(':')
+		p.skipSpace()
+		var values []any
+		if p.consume('[') {
+			if values, err = p.parseList(f); err != nil {
+				return nil, err
+			}
+		} else {
+			if !hasColon && !p.peekAny("{<") {
+				return nil, fmt.Errorf("expected ':' after %s", name)
+			}
+			v, err := p.parseValue(f)
+			if err != nil {
+				return nil, err
+			}
+			values = []any{v}
+		}
+
+		for _, v := range values {
+			switch {
+			case discard:
+			case f == nil:
+				setUnknownField(m, name, v)
+			default:
+				setField(m, f, v)
+			}
+		}
+		p.skipSpace()
+		_ = p.consume(';') || p.consume(',')
+	}
+}
+
+func (p *textParser) parseList(f *field) ([]any, error) {
+	var values []any
+	for {
+		p.skipSpace()
+		if p.consume(']') {
+			return values, nil
+		}
+		if len(values) > 0 && !p.consume(',') {
+			return nil, errors.New("expected ',' or ']'")
+		}
+		p.skipSpace()
+		v, err := p.parseValue(f)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
+
+// parseValue parses a message or scalar value of the field f, which is nil when the field is unknown.
+func (p *textParser) parseValue(f *field) (any, error) {
+	if p.peekAny("{<") {
+		end := byte('}')
+		if p.s[p.pos] == '<' {
+			end = '>'
+		}
+		p.pos++
+		switch {
+		case f == nil:
+			return p.parseMessage(nil, end)
+		case f.kind == kindMessage:
+			return p.parseMessage(f.fields, end)
+		case f.kind == kindAny:
+			return p.parseAny(end)
+		default:
+			return nil, fmt.Errorf("field %s is not a message", f.name)
+		}
+	}
+
+	if p.peekAny(`"'`) {
+		s, err := p.parseStrings()
+		if err != nil {
+			return nil, err
+		}
+		if f != nil && f.kind != kindString {
+			return nil, fmt.Errorf("field %s is not a string", f.name)
+		}
+		return s, nil
+	}
+
+	token := p.parseToken()
+	if token == "" {
+		return nil, errors.New("expected a value")
+	}
+	if f == nil {
+		return scalarValue(token), nil
+	}
+	switch f.kind {
+	case kindUint:
+		v, err := strconv.ParseInt(token, 0, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid value %q for field %s", token, f.name)
+		}
+		return v, nil
+	case kindEnum:
+		if v, err := strconv.ParseUint(token, 0, 32); err == nil {
+			return f.enumName(v), nil
+		}
+		return token, nil
+	default:
+		return nil, fmt.Errorf("invalid value %q for field %s", token, f.name)
+	}
+}
+
+// parseAny parses a google.protobuf.Any, either in its expanded form
+// "[type.googleapis.com/pkg.Type] { ... }" or with a "type_url" field.
+func (p *textParser) parseAny(end byte) (map[string]any, error) {
+	m, err := p.parseMessage(nil, end)
+	if err != nil {
+		return nil, err
+	}
+	if typeURL, ok := m["type_url"]; ok {
+		// The value of an Any can't be decoded without its schema, so only the type is kept.
+		return map[string]any{"@type": typeURL}, nil
+	}
+	return m, nil // expanded by parseMessage
+}
+
+// expandAny converts an expanded google.protobuf.Any to its YAML form, where
+// the type is the "@type" key. Other messages are returned as-is.
+func expandAny(m map[string]any) map[string]any {
+	if len(m) != 1 {
+		return m
+	}
+	for k, v := range m {
+		msg, ok := v.(map[string]any)
+		if !ok || !strings.HasPrefix(k, "[") || !strings.Contains(k, "/") {
+			return m
+		}
+		result := map[string]any{"@type": k[1 : len(k)-1]}
+		maps.Copy(result, msg)
+		return result
+	}
+	return m
+}
+
+func (p *textParser) parseFieldName() (string, error) {
+	if p.consume('[') {
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if end < 0 {
+			return "", errors.New("expected ']'")
+		}
+		name := strings.Join(strings.Fields(p.s[p.pos:p.pos+end]), "")
+		p.pos += end + 1
+		return "[" + name + "]", nil
+	}
+	start := p.pos
+	for p.pos < len(p.s) && (isLetter(p.s[p.pos]) || p.pos > start && isDigit(p.s[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", errors.New("expected a field name")
+	}
+	return p.s[start:p.pos], nil
+}
+
+// parseStrings parses adjacent quoted strings, which are concatenated.
+func (p *textParser) parseStrings() (string, error) {
+	var sb strings.Builder
+	for {
+		quote := p.s[p.pos]
+		p.pos++
+		for {
+			if p.pos >= len(p.s) || p.s[p.pos] == '\n' {
+				return "", errors.New("unterminated string")
+			}
+			c := p.s[p.pos]
+			p.pos++
+			if c == quote {
+				break
+			}
+			if c != '\\' {
+				sb.WriteByte(c)
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		}
+		p.skipSpace()
+		if !p.peekAny(`"'`) {
+			return sb.String(), nil
+		}
+	}
+}
+
+func (p *textParser) parseEscape(sb *strings.Builder) error {
+	if p.pos >= len(p.s) {
+		return errors.New("unterminated string")
+	}
+	c := p.s[p.pos]
+	p.pos++
+	switch c {
+	case 'a':
+		sb.WriteByte('\a')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '\\', '\'', '"', '?':
+		sb.WriteByte(c)
+	case 'x', 'X':
+		start := p.pos
+		for p.pos < len(p.s) && p.pos-start < 2 && isHexDigit(p.s[p.pos]) {
+			p.pos++
+		}
+		v, err := strconv.ParseUint(p.s[start:p.pos], 16, 8)
+		if err != nil {
+			return fmt.Errorf("invalid escape \\%c%s", c, p.s[start:p.pos])
+		}
+		sb.WriteByte(byte(v))
+	default:
+		if c < '0' || c > '7' {
+			return fmt.Errorf("invalid escape \\%c", c)
+		}
+		start := p.pos - 1
+		for p.pos < len(p.s) && p.pos-start < 3 && p.s[p.pos] >= '0' && p.s[p.pos] <= '7' {
+			p.pos++
+		}
+		v, err := strconv.ParseUint(p.s[start:p.pos], 8, 8)
+		if err != nil {
+			return fmt.Errorf("invalid escape \\%s", p.s[start:p.pos])
+		}
+		sb.WriteByte(byte(v))
+	}
+	return nil
+}
+
+// parseToken parses an identifier or number.
+func (p *textParser) parseToken() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if !isLetter(c) && !isDigit(c) && c != '.' && c != '-' && c != '+' {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+// skipSpace skips whitespace and comments.
+func (p *textParser) skipSpace() {
+	for p.pos < len(p.s) {
+		switch c := p.s[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.s) && p.s[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *textParser) consume(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *textParser) peekAny(chars string) bool {
+	return p.pos < len(p.s) && strings.IndexByte(chars, p.s[p.pos]) >= 0
+}
+
+func lookupField(fields map[uint64]*field, name string) *field {
+	for _, f := range fields {
+		if f.name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// setUnknownField sets a field of an unknown message, making a list when the name repeats.
+func setUnknownField(m map[string]any, name string, v any) {
+	existing, ok := m[name]
+	if !ok {
+		m[name] = v
+		return
+	}
+	if list, ok := existing.([]any); ok {
+		m[name] = append(list, v)
+		return
+	}
+	m[name] = []any{existing, v}
+}
+
+// scalarValue converts a token of an unknown field to the type YAML would.
+func scalarValue(token string) any {
+	if v, err := strconv.ParseInt(token, 0, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseFloat(token, 64); err == nil {
+		return v
+	}
+	if v, err := strconv.ParseBool(token); err == nil && (token == "true" || token == "false") {
+		return v
+	}
+	return token
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,119 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalProtoText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected map[string]any
+	}{
+		{
+			name: "separators, comments and angle brackets",
+			text: `admin: < # comment
+  address { socket_address { address: '127.0.0.1'; port_value: 0x2675, } }
+>`,
+			expected: map[string]any{"admin": map[string]any{"address": map[string]any{
+				"socket_address": map[string]any{"address": "127.0.0.1", "port_value": int64(9845)},
+			}}},
+		},
+		{
+			name: "repeated field forms",
+			text: `static_resources {
+  listeners { name: "a" }
+  listeners: [{ name: "b" }, { name: "c" }]
+}`,
+			expected: map[string]any{"static_resources": map[string]any{"listeners": []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b"},
+				map[string]any{"name": "c"},
+			}}},
+		},
+		{
+			name:     "string escapes and concatenation",
+			text:     `static_resources { listeners { name: "a\"b\\\x41\101" 'c\n' } }`,
+			expected: map[string]any{"static_resources": map[string]any{"listeners": []any{map[string]any{"name": "a\"b\\AAc\n"}}}},
+		},
+		{
+			name: "unknown fields are skipped",
+			text: `node { id: "n" metadata { fields { key: "k" value { list_value { values: [1, 2.5, true] } } } } }
+stats_flush_interval { seconds: 5 }
+static_resources { secrets: [{ name: "s" }] }`,
+			expected: map[string]any{"node": map[string]any{"id": "n"}, "static_resources": map[string]any{}},
+		},
+		{
+			name: "any with type_url",
+			text: `static_resources { listeners { listener_filters {
+  name: "tls_inspector"
+  typed_config { type_url: "type.googleapis.com/envoy.extensions.filters.listener.tls_inspector.v3.TlsInspector" value: "" }
+} } }`,
+			expected: map[string]any{"static_resources": map[string]any{"listeners": []any{map[string]any{
+				"listener_filters": []any{map[string]any{
+					"name":         "tls_inspector",
+					"typed_config": map[string]any{"@type": "type.googleapis.com/envoy.extensions.filters.listener.tls_inspector.v3.TlsInspector"},
+				}},
+			}}}},
+		},
+		{
+			name:     "protocol number",
+			text:     `admin { address { socket_address { protocol: 1 } } }`,
+			expected: map[string]any{"admin": map[string]any{"address": map[string]any{"socket_address": map[string]any{"protocol": "UDP"}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := unmarshalProtoText(tt.text, bootstrapFields)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestUnmarshalProtoText_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		expectedErr string
+	}{
+		{
+			name:        "unclosed message",
+			text:        "admin {\n  address {}",
+			expectedErr: `line 2:13: expected '}'`,
+		},
+		{
+			name:        "missing colon",
+			text:        `static_resources { listeners { name "a" } }`,
+			expectedErr: `line 1:37: expected ':' after name`,
+		},
+		{
+			name:        "wrong type",
+			text:        `admin { address { socket_address { port_value: "9901" } } }`,
+			expectedErr: `line 1:55: field port_value is not a string`,
+		},
+		{
+			name:        "negative port",
+			text:        `admin { address { socket_address { port_value: -1 } } }`,
+			expectedErr: `line 1:50: invalid value "-1" for field port_value`,
+		},
+		{
+			name:        "unterminated string",
+			text:        `admin { access_log_path: "/dev/null }`,
+			expectedErr: `line 1:38: unterminated string`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := unmarshalProtoText(tt.text, bootstrapFields)
+			require.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// progressInterval is how often download progress is logged.
+var progressInterval = time.Second
+
+// downloadTarball downloads src into the file dst. When dst already has content from a download that didn't
+// finish, this resumes it with an HTTP Range request. On error, dst is left for the next attempt to resume.
+func downloadTarball(ctx context.Context, o *globals.GlobalOpts, dst string, src version.TarballURL) (err error) { // dst, src order like io.Copy
+	if err = os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return fmt.Errorf("unable to create directory %q: %w", filepath.Dir(dst), err)
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY, 0o600) //nolint:gosec // dst is in DataHome
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	res, err := httpGetFrom(ctx, o.HTTPClient, string(src), o.UserAgent, offset)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck // body copied below
+
+	switch res.StatusCode {
+	case http.StatusPartialContent:
+		o.Logf("resuming download at %s\n", FormatBytes(offset))
+	case http.StatusOK: // nothing to resume, or the server doesn't support ranges
+		if offset > 0 {
+			if err = f.Truncate(0); err != nil {
+				return err
+			}
+			if _, err = f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			offset = 0
+		}
+	case http.StatusRequestedRangeNotSatisfiable: // dst is already complete, which the checksum will verify
+		return nil
+	default:
+		return fmt.Errorf("received %v status code from %s", res.StatusCode, src)
+	}
+
+	p := newProgress(o, offset, res.ContentLength)
+	if _, err = io.Copy(f, io.TeeReader(res.Body, p)); err != nil {
+		return fmt.Errorf("error downloading %s: %w", src, err)
+	}
+	p.log()
+	return nil
+}
+
+// verifyTarball returns an error if the file doesn't have the given SHA-256 sum.
+func verifyTarball(path string, sha256Sum version.SHA256Sum) error {
+	sum, err := sha256File(path)
+	if err != nil {
+		return err
+	}
+	if sum != sha256Sum {
+		return fmt.Errorf("expected SHA-256 sum %q, but have %q", sha256Sum, sum)
+	}
+	return nil
+}
+
+// sha256File returns the SHA-256 sum of the file at path.
+func sha256File(path string) (version.SHA256Sum, error) {
+	f, err := os.Open(path) //nolint:gosec // path is in DataHome or given by the user
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck // read-only
+
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return version.SHA256Sum(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// progress logs how much of a download completed, at most once per progressInterval.
+type progress struct {
+	o *globals.GlobalOpts
+	// offset is the size of the partial download this resumed, and total is -1 when the size is unknown.
+	offset, written, total int64
+	start, last            time.Time
+	now                    func() time.Time
+}
+
+func newProgress(o *globals.GlobalOpts, offset, contentLength int64) *progress {
+	total := int64(-1)
+	if contentLength >= 0 {
+		total = offset + contentLength
+	}
+	now := time.Now()
+	return &progress{o: o, offset: offset, total: total, start: now, last: now, now: time.Now}
+}
+
+// Write implements io.Writer by counting the bytes downloaded.
+func (p *progress) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if now := p.now(); now.Sub(p.last) >= progressInterval {
+		p.last = now
+		p.log()
+	}
+	return len(b), nil
+}
+
+func (p *progress) log() {
+	downloaded := p.offset + p.written
+	elapsed := max(p.now().Sub(p.start).Seconds(), 0.001) // avoid dividing by zero
+	rate := FormatBytes(int64(float64(p.written) / elapsed))
+	if p.total <= 0 {
+		p.o.Logf("downloaded %s at %s/s\n", FormatBytes(downloaded), rate)
+		return
+	}
+	p.o.Logf("downloaded %s of %s (%d%%) at %s/s\n", FormatBytes(downloaded), FormatBytes(p.total), downloaded*100/p.total, rate)
+}
+
+// FormatBytes formats a size in binary units, such as "60.1 MiB".
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
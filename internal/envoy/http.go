@@ -6,6 +6,7 @@ package envoy
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	neturl "net/url"
@@ -14,14 +15,40 @@ import (
 
 const userAgentHeader = "User-Agent"
 
-// httpGet GETs rawURL with a User-Agent header and one retry on transient network error.
+// fileClient serves "file://" URLs, such as an Envoy versions JSON and archives on a mounted volume. This supports
+// Range requests like an HTTP server, so downloadTarball resumes the same way.
+var fileClient = &http.Client{Transport: http.NewFileTransport(http.Dir("/"))}
+
+// httpGet GETs rawURL with a User-Agent header and one retry on transient network error. A "file://" URL is read
+// from the local filesystem instead of using client.
 func httpGet(ctx context.Context, client *http.Client, rawURL, ua string) (*http.Response, error) {
+	return httpGetWithHeader(ctx, client, rawURL, ua, nil)
+}
+
+// httpGetFrom is like httpGet, except it requests the content from offset with a Range header, when positive.
+// The server may ignore it, and respond with the whole content.
+func httpGetFrom(ctx context.Context, client *http.Client, rawURL, ua string, offset int64) (*http.Response, error) {
+	var header http.Header
+	if offset > 0 {
+		header = http.Header{"Range": {fmt.Sprintf("bytes=%d-", offset)}}
+	}
+	return httpGetWithHeader(ctx, client, rawURL, ua, header)
+}
+
+// httpGetWithHeader is like httpGet, except it adds the given header to the request, such as for a conditional GET.
+func httpGetWithHeader(ctx context.Context, client *http.Client, rawURL, ua string, header http.Header) (*http.Response, error) {
+	if isFileURL(rawURL) {
+		client = fileClient
+	}
 	// #nosec -> url can be anywhere by design
 	get := func() (*http.Response, error) {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, http.NoBody)
 		if err != nil {
 			return nil, err
 		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
 		req.Header.Add(userAgentHeader, ua)
 		return client.Do(req)
 	}
@@ -43,6 +70,12 @@ func httpGet(ctx context.Context, client *http.Client, rawURL, ua string) (*http
 	return get()
 }
 
+// isFileURL returns true if rawURL is read from the local filesystem, so doesn't need the network.
+func isFileURL(rawURL string) bool {
+	u, err := neturl.Parse(rawURL)
+	return err == nil && u.Scheme == "file"
+}
+
 // isNetError unwraps url.Error so transient dial/TLS failures are retried
 // while HTTP-level errors (4xx, 5xx) are not.
 func isNetError(err error) bool {
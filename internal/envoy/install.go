@@ -6,9 +6,7 @@ package envoy
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"os"
-	"path"
 	"path/filepath"
 	"time"
 
@@ -32,6 +30,9 @@ func InstallIfNeeded(ctx context.Context, o *globals.GlobalOpts) (string, error)
 
 	var evs *version.ReleaseVersions // Get version metadata for what we will install
 
+	if devLatest && err == nil && o.Offline { // can't check for a newer dev build
+		return verifyEnvoy(installPath)
+	}
 	if devLatest && err == nil {
 		evs, err = o.GetEnvoyVersions(ctx)
 		if err != nil {
@@ -57,6 +58,7 @@ func InstallIfNeeded(ctx context.Context, o *globals.GlobalOpts) (string, error)
 		}
 
 		var tarballURL version.TarballURL
+		var image version.Image
 		var releaseDate version.ReleaseDate
 		if v == version.Dev {
 			if evs.Dev != nil {
@@ -66,31 +68,43 @@ func InstallIfNeeded(ctx context.Context, o *globals.GlobalOpts) (string, error)
 		} else {
 			r := evs.Versions[v]
 			tarballURL = r.Tarballs[o.Platform]
+			image = r.Image
 			releaseDate = r.ReleaseDate
 		}
-		if tarballURL == "" { // Ensure there is a version for this platform
+		if tarballURL == "" && image == "" { // Ensure there is a version for this platform
 			return "", fmt.Errorf("couldn't find version %q for platform %q", v, o.Platform)
 		}
 
-		tarball := version.Tarball(path.Base(string(tarballURL)))
-		sha256Sum := evs.SHA256Sums[tarball]
-		if len(sha256Sum) != 64 {
-			return "", fmt.Errorf("couldn't find sha256Sum of version %q for platform %q: %w", v, o.Platform, err)
+		var sha256Sum version.SHA256Sum
+		if tarballURL != "" { // an image is verified by the digests of its layers instead
+			tarball := tarballURL.Tarball()
+			if sha256Sum = evs.SHA256Sums[tarball]; len(sha256Sum) != 64 {
+				return "", fmt.Errorf("couldn't find sha256Sum of version %q for platform %q: %w", v, o.Platform, err)
+			}
 		}
 
 		var mtime time.Time // Create a directory for the version, preserving the release date as its mtime
 		if mtime, err = time.Parse("2006-01-02", string(releaseDate)); err != nil {
 			return "", fmt.Errorf("couldn't find releaseDate of version %q for platform %q: %w", v, o.Platform, err)
 		}
-		if err = os.MkdirAll(installPath, 0o750); err != nil {
-			return "", fmt.Errorf("unable to create directory %q: %w", installPath, err)
-		}
-		o.Logf("downloading %s\n", tarballURL)
-		if err := untarEnvoy(ctx, o.HTTPClient, installPath, tarballURL, sha256Sum, o.UserAgent); err != nil {
+		// Another func-e sharing the same $FUNC_E_DATA_HOME may be installing the same version.
+		var unlock func()
+		if unlock, err = lockInstall(ctx, o, installPath); err != nil {
 			return "", err
 		}
-		if err = os.Chtimes(installPath, mtime, mtime); err != nil { // overwrite the mtime to preserve it in the list
-			return "", fmt.Errorf("unable to set date of directory %q: %w", installPath, err)
+		defer unlock()
+		if _, err = os.Stat(envoyPath); err == nil && !devLatest {
+			o.Logf("%s is already downloaded\n", v)
+			return verifyEnvoy(installPath)
+		}
+
+		if err = installAtomically(installPath, mtime, func(dir string) error {
+			if tarballURL == "" {
+				return pullEnvoyImage(ctx, o, dir, image)
+			}
+			return untarEnvoy(ctx, o, dir, tarballURL, sha256Sum)
+		}); err != nil {
+			return "", err
 		}
 	case err == nil:
 		o.Logf("%s is already downloaded\n", v)
@@ -101,6 +115,125 @@ func InstallIfNeeded(ctx context.Context, o *globals.GlobalOpts) (string, error)
 	return verifyEnvoy(installPath)
 }
 
+// InstallFromFile installs the Envoy archive at archivePath as version v, for example when there is no network.
+// The archive must have the given SHA-256 sum. mtime is the release date, set as the mtime of the version directory.
+func InstallFromFile(ctx context.Context, o *globals.GlobalOpts, v version.PatchVersion, archivePath string,
+	sha256Sum version.SHA256Sum, mtime time.Time,
+) (string, error) {
+	if err := verifyTarball(archivePath, sha256Sum); err != nil {
+		return "", fmt.Errorf("error verifying %s: %w", archivePath, err)
+	}
+	installPath := filepath.Join(o.EnvoyVersionsDir(), v.String())
+	unlock, err := lockInstall(ctx, o, installPath)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	if _, err = os.Stat(filepath.Join(installPath, binEnvoy)); err == nil {
+		o.Logf("%s is already downloaded\n", v)
+		return verifyEnvoy(installPath)
+	}
+
+	o.Logf("installing %s from %s\n", v, archivePath)
+	if err = installAtomically(installPath, mtime, func(dir string) error {
+		if err := untarFile(dir, archivePath); err != nil {
+			return fmt.Errorf("error untarring %s: %w", archivePath, err)
+		}
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	return verifyEnvoy(installPath)
+}
+
+// InstallFromImage installs Envoy for o.Platform from the OCI image as version v, such as an image that isn't in the
+// Envoy versions JSON. mtime is the release date, set as the mtime of the version directory.
+func InstallFromImage(ctx context.Context, o *globals.GlobalOpts, v version.PatchVersion, image version.Image, mtime time.Time) (string, error) {
+	installPath := filepath.Join(o.EnvoyVersionsDir(), v.String())
+	unlock, err := lockInstall(ctx, o, installPath)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+	if _, err = os.Stat(filepath.Join(installPath, binEnvoy)); err == nil {
+		o.Logf("%s is already downloaded\n", v)
+		return verifyEnvoy(installPath)
+	}
+
+	if err = installAtomically(installPath, mtime, func(dir string) error {
+		return pullEnvoyImage(ctx, o, dir, image)
+	}); err != nil {
+		return "", err
+	}
+	return verifyEnvoy(installPath)
+}
+
+// installAtomically extracts into a temporary sibling of installPath, then renames it into place. This ensures a
+// failed checksum, canceled context or killed process never leaves a partial installPath that looks installed.
+//
+// Temporary directories left by a previous install that didn't finish are removed first. This is safe as the
+// caller holds the lock from lockInstall.
+func installAtomically(installPath string, mtime time.Time, extract func(dir string) error) error {
+	versionsDir, name := filepath.Split(installPath)
+	if err := os.MkdirAll(versionsDir, 0o750); err != nil {
+		return fmt.Errorf("unable to create directory %q: %w", versionsDir, err)
+	}
+	partialPattern := "." + name + ".partial-*"
+	if err := removePartialInstalls(versionsDir, partialPattern); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(versionsDir, partialPattern)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for %q: %w", installPath, err)
+	}
+	defer os.RemoveAll(tmpDir) //nolint:errcheck // no-op once renamed into place
+
+	if err = os.Chmod(tmpDir, 0o750); err != nil { // MkdirTemp is 0o700
+		return err
+	}
+	if err = extract(tmpDir); err != nil {
+		return err
+	}
+	if err = os.Chtimes(tmpDir, mtime, mtime); err != nil { // overwrite the mtime to preserve it in the list
+		return fmt.Errorf("unable to set date of directory %q: %w", tmpDir, err)
+	}
+
+	// Move aside any existing directory, such as a stale dev version or one missing bin/envoy, as rename can't
+	// replace a non-empty directory.
+	oldDir := tmpDir + ".old"
+	movedAside := false
+	if err = os.Rename(installPath, oldDir); err == nil {
+		movedAside = true
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("unable to replace directory %q: %w", installPath, err)
+	}
+	if err = os.Rename(tmpDir, installPath); err != nil {
+		if movedAside {
+			_ = os.Rename(oldDir, installPath)
+		}
+		return fmt.Errorf("unable to rename %q to %q: %w", tmpDir, installPath, err)
+	}
+	if movedAside {
+		_ = os.RemoveAll(oldDir) // a leftover is removed on the next install
+	}
+	return nil
+}
+
+// removePartialInstalls removes directories matching the pattern, left by installs that didn't finish.
+func removePartialInstalls(versionsDir, pattern string) error {
+	partials, err := filepath.Glob(filepath.Join(versionsDir, pattern))
+	if err != nil {
+		return err
+	}
+	for _, p := range partials {
+		if err = os.RemoveAll(p); err != nil {
+			return fmt.Errorf("unable to remove partial install %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
 func verifyEnvoy(installPath string) (string, error) {
 	envoyPath := filepath.Join(installPath, binEnvoy)
 	stat, err := os.Stat(envoyPath)
@@ -113,20 +246,60 @@ func verifyEnvoy(installPath string) (string, error) {
 	return envoyPath, nil
 }
 
-func untarEnvoy(ctx context.Context, client *http.Client, dst string, src version.TarballURL, // dst, src order like io.Copy
-	sha256Sum version.SHA256Sum, ua string,
+// untarEnvoy downloads src into $FUNC_E_DATA_HOME/envoy-tarballs, resuming any partial download left there,
+// verifies its SHA-256 sum and then extracts it into dst. The archive is removed once extracted, unless
+// globals.GlobalOpts TarballCache is set. In that case, it is kept and reused instead of downloading again.
+//
+// When globals.GlobalOpts Offline is set, this only uses an archive already in the cache, or a "file://" src.
+func untarEnvoy(ctx context.Context, o *globals.GlobalOpts, dst string, src version.TarballURL, // dst, src order like io.Copy
+	sha256Sum version.SHA256Sum,
 ) error {
-	res, err := httpGet(ctx, client, string(src), ua)
-	if err != nil {
-		return err
+	tarballPath := filepath.Join(o.EnvoyTarballsDir(), string(src.Tarball()))
+	if o.TarballCache || o.Offline {
+		if _, err := os.Stat(tarballPath); err == nil {
+			if err = verifyTarball(tarballPath, sha256Sum); err == nil {
+				o.Logf("using cached %s\n", tarballPath)
+				if err = untarFile(dst, tarballPath); err != nil {
+					return fmt.Errorf("error untarring %s: %w", tarballPath, err)
+				}
+				return nil
+			}
+			o.Logf("removing corrupt cached %s\n", tarballPath)
+			if err = os.Remove(tarballPath); err != nil {
+				return err
+			}
+		}
 	}
-	defer res.Body.Close() //nolint:errcheck // body untarred below
 
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("received %v status code from %s", res.StatusCode, src)
+	if o.Offline && !isFileURL(string(src)) {
+		return fmt.Errorf("offline, but %s isn't in %s: install it with \"func-e install --from-file\", or run func-e without --offline",
+			src.Tarball(), o.EnvoyTarballsDir())
 	}
-	if err = tar.UntarAndVerify(dst, res.Body, sha256Sum); err != nil {
+
+	o.Logf("downloading %s\n", src)
+	partialPath := tarballPath + ".partial"
+	if err := downloadTarball(ctx, o, partialPath, src); err != nil {
+		return err
+	}
+	if err := verifyTarball(partialPath, sha256Sum); err != nil {
+		_ = os.Remove(partialPath) // corrupt, so don't resume it
+		return fmt.Errorf("error verifying %s: %w", src, err)
+	}
+	if err := untarFile(dst, partialPath); err != nil {
 		return fmt.Errorf("error untarring %s: %w", src, err)
 	}
-	return nil
+	if o.TarballCache {
+		return os.Rename(partialPath, tarballPath)
+	}
+	return os.Remove(partialPath)
+}
+
+// untarFile extracts the archive at src into dst.
+func untarFile(dst, src string) error { // dst, src order like io.Copy
+	f, err := os.Open(src) //nolint:gosec // src is in DataHome or given by the user
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck // read-only
+	return tar.Untar(dst, f)
 }
@@ -6,11 +6,14 @@ package envoy
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -26,6 +29,8 @@ import (
 
 func TestUntarEnvoyError(t *testing.T) {
 	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	notATar := []byte("mary had a little lamb")
+	notATarSHA256Sum := version.SHA256Sum(fmt.Sprintf("%x", sha256.Sum256(notATar)))
 
 	tests := []struct {
 		name        string
@@ -47,15 +52,15 @@ func TestUntarEnvoyError(t *testing.T) {
 				w.WriteHeader(http.StatusOK)
 			},
 			sha256Sum:   tarballSHA256sum,
-			expectedErr: `error untarring $URL: EOF`,
+			expectedErr: fmt.Sprintf(`error verifying $URL: expected SHA-256 sum %q, but have "%x"`, tarballSHA256sum, sha256.Sum256(nil)),
 		},
 		{
 			name: "error on not a tar",
 			handler: func(w http.ResponseWriter, _ *http.Request) {
 				w.WriteHeader(http.StatusOK)
-				w.Write([]byte("mary had a little lamb"))
+				w.Write(notATar)
 			},
-			sha256Sum:   tarballSHA256sum,
+			sha256Sum:   notATarSHA256Sum,
 			expectedErr: `error untarring $URL: gzip: invalid header`,
 		},
 		{
@@ -65,16 +70,18 @@ func TestUntarEnvoyError(t *testing.T) {
 				w.Write(tarball)
 			},
 			sha256Sum:   "cafebabe",
-			expectedErr: fmt.Sprintf(`error untarring $URL: expected SHA-256 sum "cafebabe", but have %q`, tarballSHA256sum),
+			expectedErr: fmt.Sprintf(`error verifying $URL: expected SHA-256 sum "cafebabe", but have %q`, tarballSHA256sum),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			o := &globals.GlobalOpts{DataHome: t.TempDir(), Quiet: true}
+			o.HTTPClient = httptest.HTTPClient(tt.handler)
 			dst := filepath.Join(t.TempDir(), "dst")
 			url := version.TarballURL("http://" + admin.ServerAddr + "/file.tar.gz")
 
-			err := untarEnvoy(t.Context(), httptest.HTTPClient(tt.handler), dst, url, tt.sha256Sum, globals.DefaultDevUserAgent)
+			err := untarEnvoy(t.Context(), o, dst, url, tt.sha256Sum)
 			expectedErr := strings.ReplaceAll(tt.expectedErr, "$URL", string(url))
 			require.EqualError(t, err, expectedErr)
 		})
@@ -88,14 +95,230 @@ func TestUntarEnvoy(t *testing.T) {
 	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
 	written := 0
 	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(tarball)))
 		w.WriteHeader(http.StatusOK)
 		written, _ = w.Write(tarball)
 	})
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Out: new(bytes.Buffer)}
+	o.HTTPClient = httptest.HTTPClient(handler)
 
-	err := untarEnvoy(t.Context(), httptest.HTTPClient(handler), tempDir, version.TarballURL("http://"+admin.ServerAddr), tarballSHA256sum, globals.DefaultDevUserAgent)
+	err := untarEnvoy(t.Context(), o, tempDir, version.TarballURL("http://"+admin.ServerAddr+"/envoy.tar.gz"), tarballSHA256sum)
 	require.NoError(t, err)
 	require.Equal(t, len(tarball), written)
 	require.FileExists(t, filepath.Join(tempDir, binEnvoy))
+	require.Contains(t, o.Out.(*bytes.Buffer).String(), "(100%)")
+	// The archive is removed once extracted
+	entries, err := os.ReadDir(o.EnvoyTarballsDir())
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestUntarEnvoy_Resumes(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	half := len(tarball) / 2
+
+	tests := []struct {
+		name          string
+		partial       []byte
+		handler       func(w http.ResponseWriter, r *http.Request)
+		expectedRange string
+		expectedLog   string
+	}{
+		{
+			name:    "range request",
+			partial: tarball[:half],
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(tarball))
+			},
+			expectedRange: fmt.Sprintf("bytes=%d-", half),
+			expectedLog:   fmt.Sprintf("resuming download at %s\n", FormatBytes(int64(half))),
+		},
+		{
+			name:    "range ignored",
+			partial: []byte("garbage"),
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write(tarball)
+			},
+			expectedRange: "bytes=7-",
+		},
+		{
+			name:    "already complete",
+			partial: tarball,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(tarball))
+			},
+			expectedRange: fmt.Sprintf("bytes=%d-", len(tarball)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actualRange string
+			o := &globals.GlobalOpts{DataHome: t.TempDir(), Out: new(bytes.Buffer)}
+			o.HTTPClient = httptest.HTTPClient(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actualRange = r.Header.Get("Range")
+				tt.handler(w, r)
+			}))
+			require.NoError(t, os.MkdirAll(o.EnvoyTarballsDir(), 0o750))
+			require.NoError(t, os.WriteFile(filepath.Join(o.EnvoyTarballsDir(), "envoy.tar.gz.partial"), tt.partial, 0o600))
+
+			dst := t.TempDir()
+			err := untarEnvoy(t.Context(), o, dst, version.TarballURL("http://"+admin.ServerAddr+"/envoy.tar.gz"), tarballSHA256sum)
+			require.NoError(t, err)
+			require.FileExists(t, filepath.Join(dst, binEnvoy))
+			require.Equal(t, tt.expectedRange, actualRange)
+			require.Contains(t, o.Out.(*bytes.Buffer).String(), tt.expectedLog)
+		})
+	}
+}
+
+func TestUntarEnvoy_KeepsPartialDownload(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	half := len(tarball) / 2
+	ts := httptest.NewServer(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(tarball)))
+		w.Write(tarball[:half]) // then the connection drops
+	}))
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Quiet: true}
+	o.HTTPClient = ts.Client()
+
+	url := version.TarballURL(ts.URL + "/envoy.tar.gz")
+	err := untarEnvoy(t.Context(), o, t.TempDir(), url, tarballSHA256sum)
+	require.ErrorContains(t, err, "error downloading "+string(url))
+	partial, err := os.ReadFile(filepath.Join(o.EnvoyTarballsDir(), "envoy.tar.gz.partial"))
+	require.NoError(t, err)
+	require.Equal(t, tarball[:half], partial)
+}
+
+func TestUntarEnvoy_FileURL(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	archivePath := filepath.Join(t.TempDir(), "envoy.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, tarball, 0o600))
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Out: new(bytes.Buffer)}
+	o.HTTPClient = httptest.HTTPClient(http.NotFoundHandler()) // file URLs don't use it
+	// Leave a partial download to ensure file URLs resume, too.
+	half := len(tarball) / 2
+	require.NoError(t, os.MkdirAll(o.EnvoyTarballsDir(), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(o.EnvoyTarballsDir(), "envoy.tar.gz.partial"), tarball[:half], 0o600))
+
+	dst := t.TempDir()
+	require.NoError(t, untarEnvoy(t.Context(), o, dst, version.TarballURL("file://"+archivePath), tarballSHA256sum))
+	require.FileExists(t, filepath.Join(dst, binEnvoy))
+	require.Contains(t, o.Out.(*bytes.Buffer).String(), fmt.Sprintf("resuming download at %s\n", FormatBytes(int64(half))))
+}
+
+func TestUntarEnvoy_TarballCache(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(tarball)
+	})
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Out: new(bytes.Buffer), TarballCache: true}
+	o.HTTPClient = httptest.HTTPClient(handler)
+	url := version.TarballURL("http://" + admin.ServerAddr + "/envoy.tar.gz")
+	tarballPath := filepath.Join(o.EnvoyTarballsDir(), "envoy.tar.gz")
+
+	t.Run("keeps the download", func(t *testing.T) {
+		require.NoError(t, untarEnvoy(t.Context(), o, t.TempDir(), url, tarballSHA256sum))
+		require.Equal(t, 1, requests)
+		cached, err := os.ReadFile(tarballPath)
+		require.NoError(t, err)
+		require.Equal(t, tarball, cached)
+		require.NoFileExists(t, tarballPath+".partial")
+	})
+
+	t.Run("uses the cache", func(t *testing.T) {
+		o.Out = new(bytes.Buffer)
+		dst := t.TempDir()
+		require.NoError(t, untarEnvoy(t.Context(), o, dst, url, tarballSHA256sum))
+		require.Equal(t, 1, requests)
+		require.FileExists(t, filepath.Join(dst, binEnvoy))
+		require.Equal(t, fmt.Sprintf("using cached %s\n", tarballPath), o.Out.(*bytes.Buffer).String())
+	})
+
+	t.Run("downloads over a corrupt cache", func(t *testing.T) {
+		o.Out = new(bytes.Buffer)
+		require.NoError(t, os.WriteFile(tarballPath, tarball[:10], 0o600))
+		require.NoError(t, untarEnvoy(t.Context(), o, t.TempDir(), url, tarballSHA256sum))
+		require.Equal(t, 2, requests)
+		require.Contains(t, o.Out.(*bytes.Buffer).String(), fmt.Sprintf("removing corrupt cached %s\n", tarballPath))
+		cached, err := os.ReadFile(tarballPath)
+		require.NoError(t, err)
+		require.Equal(t, tarball, cached)
+	})
+}
+
+func TestUntarEnvoy_Offline(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Out: new(bytes.Buffer), Offline: true}
+	o.HTTPClient = httptest.HTTPClient(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Fatal("unexpected request")
+	}))
+	url := version.TarballURL("http://" + admin.ServerAddr + "/envoy.tar.gz")
+
+	err := untarEnvoy(t.Context(), o, t.TempDir(), url, tarballSHA256sum)
+	require.EqualError(t, err, fmt.Sprintf(`offline, but envoy.tar.gz isn't in %s: install it with "func-e install --from-file", or run func-e without --offline`, o.EnvoyTarballsDir()))
+
+	// An archive in the cache is used, even if --tarball-cache isn't set.
+	require.NoError(t, os.MkdirAll(o.EnvoyTarballsDir(), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(o.EnvoyTarballsDir(), "envoy.tar.gz"), tarball, 0o600))
+	dst := t.TempDir()
+	require.NoError(t, untarEnvoy(t.Context(), o, dst, url, tarballSHA256sum))
+	require.FileExists(t, filepath.Join(dst, binEnvoy))
+}
+
+func TestInstallFromFile(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	archivePath := filepath.Join(t.TempDir(), "envoy.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, tarball, 0o600))
+	mtime := time.Date(2021, 5, 11, 0, 0, 0, 0, time.UTC)
+
+	t.Run("installs", func(t *testing.T) {
+		o := &globals.GlobalOpts{DataHome: t.TempDir(), Out: new(bytes.Buffer)}
+
+		envoyPath, err := InstallFromFile(t.Context(), o, version.LastKnownEnvoy, archivePath, tarballSHA256sum, mtime)
+		require.NoError(t, err)
+		installPath := filepath.Join(o.EnvoyVersionsDir(), version.LastKnownEnvoy.String())
+		require.Equal(t, filepath.Join(installPath, binEnvoy), envoyPath)
+		stat, err := os.Stat(installPath)
+		require.NoError(t, err)
+		require.Equal(t, mtime, stat.ModTime().UTC())
+		require.Equal(t, fmt.Sprintf("installing %s from %s\n", version.LastKnownEnvoy, archivePath), o.Out.(*bytes.Buffer).String())
+
+		o.Out = new(bytes.Buffer)
+		_, err = InstallFromFile(t.Context(), o, version.LastKnownEnvoy, archivePath, tarballSHA256sum, mtime)
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("%s is already downloaded\n", version.LastKnownEnvoy), o.Out.(*bytes.Buffer).String())
+	})
+
+	t.Run("wrong sha256", func(t *testing.T) {
+		o := &globals.GlobalOpts{DataHome: t.TempDir(), Quiet: true}
+		wrong := version.SHA256Sum(strings.Repeat("0", 64))
+
+		_, err := InstallFromFile(t.Context(), o, version.LastKnownEnvoy, archivePath, wrong, mtime)
+		require.EqualError(t, err, fmt.Sprintf("error verifying %s: expected SHA-256 sum %q, but have %q", archivePath, wrong, tarballSHA256sum))
+		require.NoDirExists(t, o.EnvoyVersionsDir())
+	})
+}
+
+func TestProgress(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := &globals.GlobalOpts{Out: new(bytes.Buffer)}
+	p := newProgress(o, 10<<20, 50<<20)
+	p.start, p.last = now, now
+	p.now = func() time.Time { return now }
+
+	p.Write(make([]byte, 1<<20)) // not logged until progressInterval
+	now = now.Add(2 * time.Second)
+	p.Write(make([]byte, 1<<20))
+	require.Equal(t, "downloaded 12.0 MiB of 60.0 MiB (20%) at 1.0 MiB/s\n", o.Out.(*bytes.Buffer).String())
+
+	o.Out = new(bytes.Buffer)
+	p.total = -1
+	p.log()
+	require.Equal(t, "downloaded 12.0 MiB at 1.0 MiB/s\n", o.Out.(*bytes.Buffer).String())
 }
 
 func TestInstallIfNeeded_ErrorOnIncorrectURL(t *testing.T) {
@@ -168,6 +391,24 @@ func TestInstallIfNeeded(t *testing.T) {
 			require.NoError(t, os.MkdirAll(filepath.Dir(o.EnvoyPath), 0o700))
 			require.NoError(t, os.WriteFile(o.EnvoyPath, []byte("fake"), 0o700))
 		}, version: version.LastKnownEnvoy, stdout: "already downloaded"},
+		{name: "partial install", setupV: version.LastKnownEnvoy, setup: func(t *testing.T, o *installTest) {
+			t.Helper()
+			installPath := filepath.Dir(filepath.Dir(o.EnvoyPath))
+			require.NoError(t, os.MkdirAll(installPath, 0o700))
+			require.NoError(t, os.WriteFile(filepath.Join(installPath, "partial"), []byte("fake"), 0o600))
+			require.NoError(t, os.MkdirAll(filepath.Join(o.EnvoyVersionsDir(), "."+version.LastKnownEnvoy.String()+".partial-123"), 0o700))
+		}, version: version.LastKnownEnvoy, stdout: "downloading"},
+		{name: "installed by another func-e while waiting", setupV: version.LastKnownEnvoy, setup: func(t *testing.T, o *installTest) {
+			t.Helper()
+			installPath := filepath.Dir(filepath.Dir(o.EnvoyPath))
+			unlock, err := lockInstall(o.ctx, &globals.GlobalOpts{Quiet: true}, installPath)
+			require.NoError(t, err)
+			time.AfterFunc(100*time.Millisecond, func() {
+				_ = os.MkdirAll(filepath.Dir(o.EnvoyPath), 0o700)
+				_ = os.WriteFile(o.EnvoyPath, []byte("fake"), 0o700) //nolint:gosec // test binary
+				unlock()
+			})
+		}, version: version.LastKnownEnvoy, stdout: "waiting for another func-e to finish installing " + version.LastKnownEnvoy.String() + "\n" + version.LastKnownEnvoy.String() + " is already downloaded\n"},
 		{name: "dev-latest up to date", setupV: version.Dev, setup: installDev, version: version.DevLatest},
 		{name: "dev-latest stale", setupV: version.Dev, setup: func(t *testing.T, o *installTest) {
 			t.Helper()
@@ -175,6 +416,16 @@ func TestInstallIfNeeded(t *testing.T) {
 			stale := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
 			require.NoError(t, os.Chtimes(filepath.Join(o.EnvoyVersionsDir(), "dev"), stale, stale))
 		}, version: version.DevLatest, stdout: "downloading"},
+		{name: "dev-latest stale offline", setupV: version.Dev, setup: func(t *testing.T, o *installTest) {
+			t.Helper()
+			installDev(t, o)
+			stale := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+			require.NoError(t, os.Chtimes(filepath.Join(o.EnvoyVersionsDir(), "dev"), stale, stale))
+			o.Offline = true
+			o.GetEnvoyVersions = func(_ context.Context) (*version.ReleaseVersions, error) {
+				return nil, errors.New("unexpected lookup")
+			}
+		}, version: version.DevLatest},
 		{name: "dev wrong platform", setupV: version.Dev, setup: func(t *testing.T, o *installTest) {
 			t.Helper()
 			o.Platform = "windows/amd64"
@@ -206,10 +457,66 @@ func TestInstallIfNeeded(t *testing.T) {
 			if tt.stdout != "" {
 				require.Contains(t, o.Out.(*bytes.Buffer).String(), tt.stdout)
 			}
+			// Only complete versions are left in the versions directory, besides any install lock file.
+			entries, err := os.ReadDir(o.EnvoyVersionsDir())
+			require.NoError(t, err)
+			var dirs []string
+			for _, e := range entries {
+				if e.IsDir() {
+					dirs = append(dirs, e.Name())
+				}
+			}
+			require.Len(t, dirs, 1)
+			require.NoFileExists(t, filepath.Join(filepath.Dir(filepath.Dir(envoyPath)), "partial"))
 		})
 	}
 }
 
+func TestInstallAtomically(t *testing.T) {
+	mtime := time.Date(2021, 5, 11, 0, 0, 0, 0, time.UTC)
+	extractEnvoy := func(dir string) error {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "bin"), 0o750))
+		return os.WriteFile(filepath.Join(dir, binEnvoy), []byte("envoy"), 0o700) //nolint:gosec // test binary
+	}
+
+	t.Run("renames into place", func(t *testing.T) {
+		installPath := filepath.Join(t.TempDir(), "versions", version.LastKnownEnvoy.String())
+
+		require.NoError(t, installAtomically(installPath, mtime, extractEnvoy))
+		require.FileExists(t, filepath.Join(installPath, binEnvoy))
+		stat, err := os.Stat(installPath)
+		require.NoError(t, err)
+		require.Equal(t, mtime, stat.ModTime().UTC())
+	})
+
+	t.Run("replaces existing", func(t *testing.T) {
+		installPath := filepath.Join(t.TempDir(), version.Dev.String())
+		require.NoError(t, os.MkdirAll(installPath, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(installPath, "stale"), []byte("stale"), 0o600))
+
+		require.NoError(t, installAtomically(installPath, mtime, extractEnvoy))
+		require.FileExists(t, filepath.Join(installPath, binEnvoy))
+		require.NoFileExists(t, filepath.Join(installPath, "stale"))
+		entries, err := os.ReadDir(filepath.Dir(installPath))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("leaves nothing on error", func(t *testing.T) {
+		versionsDir := t.TempDir()
+		installPath := filepath.Join(versionsDir, version.LastKnownEnvoy.String())
+
+		err := installAtomically(installPath, mtime, func(dir string) error {
+			require.NoError(t, extractEnvoy(dir))
+			return errors.New("expected SHA-256 sum")
+		})
+		require.EqualError(t, err, "expected SHA-256 sum")
+		entries, err := os.ReadDir(versionsDir)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
 func TestVerifyEnvoy(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -0,0 +1,72 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+var (
+	// installLockTimeout is how long to wait for another func-e to install the same version.
+	installLockTimeout = 10 * time.Minute
+	// installLockPollInterval is how often a waiting func-e checks if the lock was released.
+	installLockPollInterval = 200 * time.Millisecond
+)
+
+// lockInstall acquires an exclusive lock on a file beside installPath, which is shared by all func-e processes using
+// the same $FUNC_E_DATA_HOME. This is an OS file lock (flock or LockFileEx), so the kernel releases it when its owner exits, even if
+// killed. Unlike a pid in the file, this works across PID namespaces, such as containers sharing $FUNC_E_DATA_HOME.
+//
+// The lock file is never removed: a waiter may have it open, and removing it would let a third process lock a new
+// file with the same name while the waiter locks the old one.
+//
+// The caller must invoke the returned function to release the lock.
+func lockInstall(ctx context.Context, o *globals.GlobalOpts, installPath string) (unlock func(), err error) {
+	versionsDir, name := filepath.Split(installPath)
+	if err = os.MkdirAll(versionsDir, 0o750); err != nil {
+		return nil, fmt.Errorf("unable to create directory %q: %w", versionsDir, err)
+	}
+	lockPath := filepath.Join(versionsDir, "."+name+".lock")
+	f, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0o600) //nolint:gosec // lockPath is in DataHome
+	if err != nil {
+		return nil, fmt.Errorf("unable to create lock file %q: %w", lockPath, err)
+	}
+
+	deadline := time.Now().Add(installLockTimeout)
+	logged := false
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("unable to lock %q: %w", lockPath, err)
+		} else if locked {
+			return func() {
+				_ = unlockFile(f)
+				_ = f.Close()
+			}, nil
+		}
+
+		if !logged {
+			o.Logf("waiting for another func-e to finish installing %s\n", name)
+			logged = true
+		}
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("timed out after %s waiting for another func-e to finish installing %s, "+
+				"which holds a lock on %s", installLockTimeout, name, lockPath)
+		}
+		select {
+		case <-ctx.Done():
+			_ = f.Close()
+			return nil, ctx.Err()
+		case <-time.After(installLockPollInterval):
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+func TestLockInstall(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "envoy-versions", "1.31.2")
+	lockPath := filepath.Join(filepath.Dir(installPath), ".1.31.2.lock")
+	o := &globals.GlobalOpts{Out: new(bytes.Buffer)}
+
+	unlock, err := lockInstall(t.Context(), o, installPath)
+	require.NoError(t, err)
+	require.FileExists(t, lockPath)
+	unlock()
+
+	// The lock file is kept, but released, so it can be locked again without waiting.
+	require.FileExists(t, lockPath)
+	unlock, err = lockInstall(t.Context(), o, installPath)
+	require.NoError(t, err)
+	unlock()
+	require.Empty(t, o.Out.(*bytes.Buffer))
+}
+
+func TestLockInstall_WaitsForOwner(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "1.31.2")
+	o := &globals.GlobalOpts{Out: new(bytes.Buffer)}
+	setInstallLockTiming(t, time.Minute, 10*time.Millisecond)
+
+	unlockOther, err := lockInstall(t.Context(), &globals.GlobalOpts{Quiet: true}, installPath)
+	require.NoError(t, err)
+	time.AfterFunc(100*time.Millisecond, unlockOther)
+
+	unlock, err := lockInstall(t.Context(), o, installPath)
+	require.NoError(t, err)
+	defer unlock()
+	require.Equal(t, "waiting for another func-e to finish installing 1.31.2\n", o.Out.(*bytes.Buffer).String())
+}
+
+func TestLockInstall_Timeout(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "1.31.2")
+	lockPath := filepath.Join(filepath.Dir(installPath), ".1.31.2.lock")
+	setInstallLockTiming(t, 50*time.Millisecond, 10*time.Millisecond)
+
+	unlockOther, err := lockInstall(t.Context(), &globals.GlobalOpts{Quiet: true}, installPath)
+	require.NoError(t, err)
+	defer unlockOther()
+
+	_, err = lockInstall(t.Context(), &globals.GlobalOpts{Quiet: true}, installPath)
+	require.EqualError(t, err, "timed out after 50ms waiting for another func-e to finish installing 1.31.2, "+
+		"which holds a lock on "+lockPath)
+}
+
+func setInstallLockTiming(t *testing.T, timeout, pollInterval time.Duration) {
+	t.Helper()
+	oldTimeout, oldPollInterval := installLockTimeout, installLockPollInterval
+	installLockTimeout, installLockPollInterval = timeout, pollInterval
+	t.Cleanup(func() {
+		installLockTimeout, installLockPollInterval = oldTimeout, oldPollInterval
+	})
+}
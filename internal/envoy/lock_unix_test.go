@@ -0,0 +1,50 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unix
+
+package envoy
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+)
+
+// TestLockInstall_OwnerExited ensures a lock file left by a process that exited, even if killed, doesn't block, as
+// the kernel released its lock. Its contents, such as a pid from an older func-e, don't matter.
+func TestLockInstall_OwnerExited(t *testing.T) {
+	installPath := filepath.Join(t.TempDir(), "1.31.2")
+	lockPath := filepath.Join(filepath.Dir(installPath), ".1.31.2.lock")
+	require.NoError(t, os.WriteFile(lockPath, []byte("12345"), 0o600))
+
+	// Lock it from a process that is killed while holding it. flock(1) forks, so kill its process group.
+	cmd := exec.Command("flock", lockPath, "sleep", "60")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		t.Skip("flock command not available:", err)
+	}
+	o := &globals.GlobalOpts{Quiet: true}
+	setInstallLockTiming(t, 50*time.Millisecond, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		unlock, err := lockInstall(t.Context(), o, installPath)
+		if err != nil {
+			return true // the child holds the lock
+		}
+		unlock()
+		return false
+	}, 5*time.Second, 50*time.Millisecond)
+	require.NoError(t, syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL))
+	_ = cmd.Wait()
+
+	unlock, err := lockInstall(t.Context(), o, installPath)
+	require.NoError(t, err)
+	unlock()
+}
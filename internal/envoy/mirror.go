@@ -0,0 +1,305 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/tar"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// mirrorArchivePattern matches the name of an Envoy archive in a mirror, such as "envoy-v1.31.2-linux-amd64.tar.xz"
+// or "envoy-dev-darwin-arm64.tar.gz". The submatches are the version, "dev", the OS and the architecture.
+var mirrorArchivePattern = regexp.MustCompile(`^envoy-(?:v?(\d+\.\d+\.\d+(?:_debug)?)|(dev))-([a-z0-9]+)-([a-z0-9_]+)\.tar\.(?:xz|gz)$`)
+
+// mirrorArchs maps architecture names used in some archive names to Go ones, as used in version.Platform.
+var mirrorArchs = map[string]string{"x86_64": "amd64", "aarch64": "arm64"}
+
+// mirrorArchive is a file named like an Envoy archive, found by findMirrorArchives.
+type mirrorArchive struct {
+	path string
+	// version is a version.PatchVersion, or "dev".
+	version  string
+	platform version.Platform
+	modTime  time.Time
+}
+
+// findMirrorArchives returns the files in dir and its subdirectories named like an Envoy archive.
+func findMirrorArchives(dir string) ([]mirrorArchive, error) {
+	var result []mirrorArchive
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		m := mirrorArchivePattern.FindStringSubmatch(d.Name())
+		if m == nil {
+			return nil
+		}
+		arch := m[4]
+		if goarch, ok := mirrorArchs[arch]; ok {
+			arch = goarch
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		result = append(result, mirrorArchive{path, m[1] + m[2], version.Platform(m[3] + "/" + arch), info.ModTime()})
+		return nil
+	})
+	return result, err
+}
+
+// GenerateVersions returns the Envoy versions JSON for the archives in dir and its subdirectories, which are served
+// from baseURL. Files not named like an Envoy archive are skipped.
+//
+// The release date of a version is its oldest archive's modification time, unless releaseDate isn't empty.
+// devCommitSha is required when there are "dev" archives, as it is in the "dev" entry.
+func GenerateVersions(dir, baseURL string, releaseDate version.ReleaseDate, devCommitSha string) (*version.ReleaseVersions, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if base.Path == "" || base.Path[len(base.Path)-1] != '/' {
+		base.Path += "/" // so that archive paths are resolved under it
+	}
+	archives, err := findMirrorArchives(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) == 0 {
+		return nil, fmt.Errorf("no Envoy archives in %s, named like %q", dir, "envoy-v"+version.LastKnownEnvoy.String()+"-linux-amd64.tar.xz")
+	}
+
+	result := newReleaseVersions()
+	oldest := map[string]time.Time{} // by version, to default the release date
+	for _, a := range archives {
+		rel, err := filepath.Rel(dir, a.path)
+		if err != nil {
+			return nil, err
+		}
+		tarballURL := version.TarballURL(base.ResolveReference(&url.URL{Path: filepath.ToSlash(rel)}).String())
+		if err = addMirrorArchive(result, a, tarballURL); err != nil {
+			return nil, err
+		}
+		if t, ok := oldest[a.version]; !ok || a.modTime.Before(t) {
+			oldest[a.version] = a.modTime
+		}
+	}
+
+	dateOf := func(v string) version.ReleaseDate {
+		if releaseDate != "" {
+			return releaseDate
+		}
+		return version.ReleaseDate(oldest[v].UTC().Format("2006-01-02"))
+	}
+	for v, r := range result.Versions {
+		r.ReleaseDate = dateOf(string(v))
+		result.Versions[v] = r
+	}
+	if result.Dev != nil {
+		if devCommitSha == "" {
+			return nil, fmt.Errorf("missing the commit SHA of the dev archives in %s: use --dev-commit-sha", dir)
+		}
+		result.Dev.CommitSha = devCommitSha
+		result.Dev.ReleaseDate = dateOf(version.Dev.String())
+	}
+	return result, nil
+}
+
+func newReleaseVersions() *version.ReleaseVersions {
+	return &version.ReleaseVersions{
+		Versions:   map[version.PatchVersion]version.Release{},
+		SHA256Sums: map[version.Tarball]version.SHA256Sum{},
+	}
+}
+
+// addMirrorArchive adds the archive a to evs, served from tarballURL, without a release date.
+//
+// This errs if evs already has an archive of the same file name, as SHA-256 sums are keyed by it.
+func addMirrorArchive(evs *version.ReleaseVersions, a mirrorArchive, tarballURL version.TarballURL) error {
+	tarball := version.Tarball(filepath.Base(a.path))
+	if _, ok := evs.SHA256Sums[tarball]; ok {
+		return fmt.Errorf("more than one archive named %s: %s", tarball, a.path)
+	}
+	sha256Sum, err := sha256File(a.path)
+	if err != nil {
+		return err
+	}
+	evs.SHA256Sums[tarball] = sha256Sum
+
+	if a.version == version.Dev.String() {
+		if evs.Dev == nil {
+			evs.Dev = &version.DevRelease{Tarballs: map[version.Platform]version.TarballURL{}}
+		}
+		evs.Dev.Tarballs[a.platform] = tarballURL
+		return nil
+	}
+	v := version.PatchVersion(a.version)
+	r, ok := evs.Versions[v]
+	if !ok {
+		r.Tarballs = map[version.Platform]version.TarballURL{}
+	}
+	r.Tarballs[a.platform] = tarballURL
+	evs.Versions[v] = r
+	return nil
+}
+
+// MergeVersions adds the versions generated to existing, so that the entries of existing are kept, unless generated
+// has an archive for the same version and platform. The release date of a version in existing is kept, and the "dev"
+// entry of generated replaces the one in existing.
+func MergeVersions(existing, generated *version.ReleaseVersions) {
+	if existing.Versions == nil {
+		existing.Versions = map[version.PatchVersion]version.Release{}
+	}
+	if existing.SHA256Sums == nil {
+		existing.SHA256Sums = map[version.Tarball]version.SHA256Sum{}
+	}
+	for v, g := range generated.Versions {
+		r, ok := existing.Versions[v]
+		if !ok {
+			existing.Versions[v] = g
+			continue
+		}
+		if r.Tarballs == nil {
+			r.Tarballs = map[version.Platform]version.TarballURL{}
+		}
+		maps.Copy(r.Tarballs, g.Tarballs)
+		existing.Versions[v] = r
+	}
+	maps.Copy(existing.SHA256Sums, generated.SHA256Sums)
+	if generated.Dev != nil {
+		existing.Dev = generated.Dev
+	}
+}
+
+// Mirror is an http.Handler that serves "/envoy-versions.json" and the archives it lists, so that other func-e can
+// use it as their $ENVOY_VERSIONS_URL. Tarball URLs are relative, so the mirror doesn't need to know its own URL.
+type Mirror struct {
+	// Versions is the Envoy versions JSON served.
+	Versions     *version.ReleaseVersions
+	versionsJSON []byte
+	// archives maps the name of each archive served to its path.
+	archives map[string]string
+}
+
+// NewMirror returns a Mirror of the archives in o.EnvoyTarballsDir() and the versions installed in
+// o.EnvoyVersionsDir(). An installed version without an archive for o.Platform is archived into workDir, which the
+// caller removes after serving. The "dev" version isn't served, as its commit isn't known.
+//
+// Release dates are from the cached Envoy versions JSON, or else the modification time of the installed version,
+// which is preserved on install. A cached archive of a version in neither uses its modification time, which is
+// usually when it was downloaded.
+func NewMirror(ctx context.Context, o *globals.GlobalOpts, workDir string) (*Mirror, error) {
+	m := &Mirror{Versions: newReleaseVersions(), archives: map[string]string{}}
+	releaseDates := map[version.PatchVersion]version.ReleaseDate{}
+	if o.GetCachedEnvoyVersions != nil {
+		if evs, err := o.GetCachedEnvoyVersions(ctx); err == nil {
+			for v, r := range evs.Versions {
+				if r.ReleaseDate != "" {
+					releaseDates[v] = r.ReleaseDate
+				}
+			}
+		}
+	}
+	archived := map[version.PatchVersion]mirrorArchive{}
+
+	archives, err := findMirrorArchives(o.EnvoyTarballsDir())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, a := range archives {
+		if a.version == version.Dev.String() {
+			continue
+		}
+		if err = m.add(a); err != nil {
+			return nil, err
+		}
+		archived[version.PatchVersion(a.version)] = a
+	}
+
+	installed, err := os.ReadDir(o.EnvoyVersionsDir())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	goos, goarch, _ := strings.Cut(string(o.Platform), "/")
+	for _, d := range installed {
+		v := version.NewPatchVersion(d.Name())
+		installPath := filepath.Join(o.EnvoyVersionsDir(), d.Name())
+		if v == "" || !d.IsDir() {
+			continue
+		}
+		if _, err = os.Stat(filepath.Join(installPath, binEnvoy)); err != nil {
+			continue // not completely installed
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := releaseDates[v]; !ok {
+			releaseDates[v] = version.ReleaseDate(info.ModTime().UTC().Format("2006-01-02")) // preserved on install
+		}
+		if _, ok := m.Versions.Versions[v].Tarballs[o.Platform]; ok {
+			continue
+		}
+
+		// TarGz is reproducible, so mirrors of the same installed version have the same SHA-256 sum.
+		tarballPath := filepath.Join(workDir, fmt.Sprintf("envoy-v%s-%s-%s.tar.gz", v, goos, goarch))
+		if err = tar.TarGz(tarballPath, installPath); err != nil {
+			return nil, fmt.Errorf("error archiving %s: %w", installPath, err)
+		}
+		if err = m.add(mirrorArchive{path: tarballPath, version: v.String(), platform: o.Platform}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, v := range slices.Sorted(maps.Keys(m.Versions.Versions)) {
+		r := m.Versions.Versions[v]
+		if r.ReleaseDate = releaseDates[v]; r.ReleaseDate == "" {
+			a := archived[v]
+			r.ReleaseDate = version.ReleaseDate(a.modTime.UTC().Format("2006-01-02"))
+			o.Logf("using the modification time of %s as the release date of %s, as it isn't in the Envoy versions JSON\n", a.path, v)
+		}
+		m.Versions.Versions[v] = r
+	}
+	if m.versionsJSON, err = json.Marshal(m.Versions); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *Mirror) add(a mirrorArchive) error {
+	name := filepath.Base(a.path)
+	m.archives[name] = a.path
+	return addMirrorArchive(m.Versions, a, version.TarballURL(name))
+}
+
+// ServeHTTP implements http.Handler. Archives are served with Range support, so that downloads can resume.
+func (m *Mirror) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/envoy-versions.json" {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(m.versionsJSON)
+		return
+	}
+	p, ok := m.archives[strings.TrimPrefix(r.URL.Path, "/")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.ServeFile(w, r, p)
+}
@@ -0,0 +1,226 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/admin"
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/test"
+	"github.com/tetratelabs/func-e/internal/test/httptest"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+func TestGenerateVersions(t *testing.T) {
+	dir := t.TempDir()
+	writeArchive := func(name, content, mtime string) {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		tm, err := time.Parse("2006-01-02", mtime)
+		require.NoError(t, err)
+		require.NoError(t, os.Chtimes(path, tm, tm))
+	}
+	writeArchive("1.31.2/envoy-v1.31.2-linux-amd64.tar.xz", "a", "2024-09-20")
+	writeArchive("1.31.2/envoy-v1.31.2-darwin-aarch64.tar.xz", "b", "2024-09-19")
+	writeArchive("envoy-1.30.0_debug-linux-x86_64.tar.gz", "c", "2024-04-01")
+	writeArchive("envoy-dev-linux-arm64.tar.xz", "d", "2025-01-20")
+	writeArchive("envoy-v1.31.2-linux-amd64.tar.xz.sig", "not an archive", "2024-09-20")
+
+	evs, err := GenerateVersions(dir, "https://example.com/envoy", "", "92c6cb58")
+	require.NoError(t, err)
+	require.Equal(t, &version.ReleaseVersions{
+		Versions: map[version.PatchVersion]version.Release{
+			"1.31.2": {ReleaseDate: "2024-09-19", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64":  "https://example.com/envoy/1.31.2/envoy-v1.31.2-linux-amd64.tar.xz",
+				"darwin/arm64": "https://example.com/envoy/1.31.2/envoy-v1.31.2-darwin-aarch64.tar.xz",
+			}},
+			"1.30.0_debug": {ReleaseDate: "2024-04-01", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64": "https://example.com/envoy/envoy-1.30.0_debug-linux-x86_64.tar.gz",
+			}},
+		},
+		SHA256Sums: map[version.Tarball]version.SHA256Sum{
+			"envoy-v1.31.2-linux-amd64.tar.xz":       "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+			"envoy-v1.31.2-darwin-aarch64.tar.xz":    "3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d",
+			"envoy-1.30.0_debug-linux-x86_64.tar.gz": "2e7d2c03a9507ae265ecf5b5356885a53393a2029d241394997265a1a25aefc6",
+			"envoy-dev-linux-arm64.tar.xz":           "18ac3e7343f016890c510e93f935261169d9e3f565436429830faf0934f4f8e4",
+		},
+		Dev: &version.DevRelease{
+			ReleaseDate: "2025-01-20",
+			CommitSha:   "92c6cb58",
+			Tarballs:    map[version.Platform]version.TarballURL{"linux/arm64": "https://example.com/envoy/envoy-dev-linux-arm64.tar.xz"},
+		},
+	}, evs)
+	require.NoError(t, evs.Validate())
+
+	t.Run("release date", func(t *testing.T) {
+		evs, err := GenerateVersions(dir, "https://example.com/envoy/", "2025-02-01", "92c6cb58")
+		require.NoError(t, err)
+		require.Equal(t, version.ReleaseDate("2025-02-01"), evs.Versions["1.31.2"].ReleaseDate)
+		require.Equal(t, version.ReleaseDate("2025-02-01"), evs.Dev.ReleaseDate)
+		require.Equal(t, version.TarballURL("https://example.com/envoy/envoy-dev-linux-arm64.tar.xz"), evs.Dev.Tarballs["linux/arm64"])
+	})
+
+	t.Run("dev without commit", func(t *testing.T) {
+		_, err := GenerateVersions(dir, "https://example.com/envoy", "", "")
+		require.EqualError(t, err, "missing the commit SHA of the dev archives in "+dir+": use --dev-commit-sha")
+	})
+
+	t.Run("same archive name in different directories", func(t *testing.T) {
+		dir := t.TempDir()
+		for _, d := range []string{"a", "b"} {
+			path := filepath.Join(dir, d, "envoy-v1.31.2-linux-amd64.tar.xz")
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+			require.NoError(t, os.WriteFile(path, []byte(d), 0o600))
+		}
+		_, err := GenerateVersions(dir, "https://example.com/envoy", "", "")
+		require.EqualError(t, err, "more than one archive named envoy-v1.31.2-linux-amd64.tar.xz: "+filepath.Join(dir, "b", "envoy-v1.31.2-linux-amd64.tar.xz"))
+	})
+
+	t.Run("no archives", func(t *testing.T) {
+		empty := t.TempDir()
+		_, err := GenerateVersions(empty, "https://example.com/envoy", "", "")
+		require.EqualError(t, err, "no Envoy archives in "+empty+`, named like "envoy-v`+version.LastKnownEnvoy.String()+`-linux-amd64.tar.xz"`)
+	})
+}
+
+func TestMergeVersions(t *testing.T) {
+	existing := &version.ReleaseVersions{
+		Versions: map[version.PatchVersion]version.Release{
+			"1.30.0": {ReleaseDate: "2024-04-01", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64": "https://example.com/envoy-v1.30.0-linux-amd64.tar.xz",
+			}},
+			"1.31.2": {ReleaseDate: "2024-09-19", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64":  "https://example.com/envoy-v1.31.2-linux-amd64.tar.xz",
+				"darwin/arm64": "https://example.com/envoy-v1.31.2-darwin-arm64.tar.xz",
+			}},
+		},
+		SHA256Sums: map[version.Tarball]version.SHA256Sum{
+			"envoy-v1.30.0-linux-amd64.tar.xz":  "a",
+			"envoy-v1.31.2-linux-amd64.tar.xz":  "b",
+			"envoy-v1.31.2-darwin-arm64.tar.xz": "c",
+		},
+		Dev: &version.DevRelease{ReleaseDate: "2024-09-01", CommitSha: "11111111"},
+	}
+	generated := &version.ReleaseVersions{
+		Versions: map[version.PatchVersion]version.Release{
+			"1.31.2": {ReleaseDate: "2025-01-01", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64": "https://mirror.example.com/envoy-v1.31.2-linux-amd64.tar.xz",
+			}},
+			"1.32.0": {ReleaseDate: "2025-01-01", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64": "https://mirror.example.com/envoy-v1.32.0-linux-amd64.tar.xz",
+			}},
+		},
+		SHA256Sums: map[version.Tarball]version.SHA256Sum{
+			"envoy-v1.31.2-linux-amd64.tar.xz": "d",
+			"envoy-v1.32.0-linux-amd64.tar.xz": "e",
+		},
+		Dev: &version.DevRelease{ReleaseDate: "2025-01-01", CommitSha: "22222222"},
+	}
+
+	MergeVersions(existing, generated)
+	require.Equal(t, &version.ReleaseVersions{
+		Versions: map[version.PatchVersion]version.Release{
+			"1.30.0": {ReleaseDate: "2024-04-01", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64": "https://example.com/envoy-v1.30.0-linux-amd64.tar.xz",
+			}},
+			"1.31.2": {ReleaseDate: "2024-09-19", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64":  "https://mirror.example.com/envoy-v1.31.2-linux-amd64.tar.xz",
+				"darwin/arm64": "https://example.com/envoy-v1.31.2-darwin-arm64.tar.xz",
+			}},
+			"1.32.0": {ReleaseDate: "2025-01-01", Tarballs: map[version.Platform]version.TarballURL{
+				"linux/amd64": "https://mirror.example.com/envoy-v1.32.0-linux-amd64.tar.xz",
+			}},
+		},
+		SHA256Sums: map[version.Tarball]version.SHA256Sum{
+			"envoy-v1.30.0-linux-amd64.tar.xz":  "a",
+			"envoy-v1.31.2-linux-amd64.tar.xz":  "d",
+			"envoy-v1.31.2-darwin-arm64.tar.xz": "c",
+			"envoy-v1.32.0-linux-amd64.tar.xz":  "e",
+		},
+		Dev: &version.DevRelease{ReleaseDate: "2025-01-01", CommitSha: "22222222"},
+	}, existing)
+}
+
+func TestNewMirror(t *testing.T) {
+	tarball, tarballSHA256sum := test.RequireFakeEnvoyTarGz(t, version.LastKnownEnvoy)
+	archivePath := filepath.Join(t.TempDir(), "envoy.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, tarball, 0o600))
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Platform: "linux/amd64", Quiet: true}
+	mtime := time.Date(2021, 5, 11, 0, 0, 0, 0, time.UTC)
+	_, err := InstallFromFile(t.Context(), o, version.LastKnownEnvoy, archivePath, tarballSHA256sum, mtime)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(o.EnvoyTarballsDir(), 0o700))
+	var cached []string
+	for _, name := range []string{"envoy-v1.2.3-darwin-arm64.tar.xz", "envoy-v1.2.4-darwin-arm64.tar.xz"} {
+		p := filepath.Join(o.EnvoyTarballsDir(), name)
+		require.NoError(t, os.WriteFile(p, []byte("a"), 0o600))
+		require.NoError(t, os.Chtimes(p, mtime, mtime))
+		cached = append(cached, p)
+	}
+
+	// The release date of a cached archive is from the versions JSON, as its modification time is when downloaded.
+	o.Quiet, o.Out = false, new(bytes.Buffer)
+	o.GetCachedEnvoyVersions = func(context.Context) (*version.ReleaseVersions, error) {
+		return &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+			"1.2.3": {ReleaseDate: "2020-01-02"},
+		}}, nil
+	}
+	m, err := NewMirror(t.Context(), o, t.TempDir())
+	require.NoError(t, err)
+	tarballName := version.Tarball("envoy-v" + version.LastKnownEnvoy.String() + "-linux-amd64.tar.gz")
+	require.Equal(t, map[version.PatchVersion]version.Release{
+		version.LastKnownEnvoy: {ReleaseDate: "2021-05-11", Tarballs: map[version.Platform]version.TarballURL{
+			"linux/amd64": version.TarballURL(tarballName),
+		}},
+		"1.2.3": {ReleaseDate: "2020-01-02", Tarballs: map[version.Platform]version.TarballURL{
+			"darwin/arm64": "envoy-v1.2.3-darwin-arm64.tar.xz",
+		}},
+		"1.2.4": {ReleaseDate: "2021-05-11", Tarballs: map[version.Platform]version.TarballURL{
+			"darwin/arm64": "envoy-v1.2.4-darwin-arm64.tar.xz",
+		}},
+	}, m.Versions.Versions)
+	require.NoError(t, m.Versions.Validate())
+	require.Equal(t, fmt.Sprintf("using the modification time of %s as the release date of 1.2.4, as it isn't in the Envoy versions JSON\n", cached[1]),
+		o.Out.(*bytes.Buffer).String())
+
+	t.Run("reproducible", func(t *testing.T) {
+		again, err := NewMirror(t.Context(), o, t.TempDir())
+		require.NoError(t, err)
+		require.Equal(t, m.Versions.SHA256Sums[tarballName], again.Versions.SHA256Sums[tarballName])
+	})
+
+	t.Run("installs", func(t *testing.T) {
+		versionsURL := "http://" + admin.ServerAddr + "/envoy-versions.json"
+		client := &globals.GlobalOpts{DataHome: t.TempDir(), Platform: o.Platform, EnvoyVersion: version.LastKnownEnvoy, Quiet: true}
+		client.HTTPClient = httptest.HTTPClient(m)
+		client.GetEnvoyVersions = NewGetVersions(client.HTTPClient, versionsURL, globals.DefaultDevUserAgent)
+
+		envoyPath, err := InstallIfNeeded(t.Context(), client)
+		require.NoError(t, err)
+		require.FileExists(t, envoyPath)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client := httptest.HTTPClient(m)
+		for _, p := range []string{"/", "/envoy.tar.gz", "/tarballs/envoy-v1.2.3-darwin-arm64.tar.xz"} {
+			req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://"+admin.ServerAddr+p, nil)
+			require.NoError(t, err)
+			res, err := client.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+			require.Equal(t, http.StatusNotFound, res.StatusCode, p)
+		}
+	})
+}
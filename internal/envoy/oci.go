@@ -0,0 +1,460 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	archivetar "archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// imageEnvoyPath is where the official Envoy images have the binary, relative to the root of the image filesystem.
+const imageEnvoyPath = "usr/local/bin/envoy"
+
+// Media types of OCI and Docker image manifests and layers, which are compatible.
+const (
+	mediaTypeOCIIndex        = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerList      = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIManifest     = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifest  = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCILayer        = "application/vnd.oci.image.layer.v1.tar"
+	mediaTypeOCILayerGzip    = "application/vnd.oci.image.layer.v1.tar+gzip"
+	mediaTypeDockerLayerGzip = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+)
+
+// manifestAccept is the Accept header of a manifest request, as a registry may convert to any type not listed.
+const manifestAccept = mediaTypeOCIIndex + ", " + mediaTypeDockerList + ", " + mediaTypeOCIManifest + ", " + mediaTypeDockerManifest
+
+const (
+	// maxManifestSize limits what is read of a manifest or token response, which are small.
+	maxManifestSize = 4 << 20
+	// dockerHub is the registry of an image without one, which has a different API host.
+	dockerHub        = "docker.io"
+	dockerHubAPIHost = "registry-1.docker.io"
+	// whiteoutPrefix marks a file deleted by a layer, and opaqueWhiteout all files in its directory.
+	whiteoutPrefix = ".wh."
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+var (
+	repositoryPattern = regexp.MustCompile(`^[a-z0-9]+(?:[._-]+[a-z0-9]+)*(?:/[a-z0-9]+(?:[._-]+[a-z0-9]+)*)*$`)
+	tagPattern        = regexp.MustCompile(`^\w[\w.-]{0,127}$`)
+	digestPattern     = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+	// challengeParamPattern matches the parameters of a WWW-Authenticate challenge, such as `realm="https://..."`.
+	challengeParamPattern = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// imageRef is a parsed version.Image.
+type imageRef struct {
+	// registry is the host and optional port of the registry, such as "docker.io" or "localhost:5000".
+	registry string
+	// repository is the name of the image in the registry, such as "envoyproxy/envoy".
+	repository string
+	// reference is a tag, such as "v1.31.2", or a digest, such as "sha256:...".
+	reference string
+}
+
+// parseImageRef parses image like "docker pull" does: without a registry, the image is on Docker Hub, and a name
+// without a slash is an official image there. Without a tag or digest, the tag is "latest".
+func parseImageRef(image version.Image) (imageRef, error) {
+	name, reference := string(image), "latest"
+	if i := strings.Index(name, "@"); i >= 0 { // a digest takes precedence over any tag
+		name, reference = name[:i], name[i+1:]
+		if j := strings.LastIndex(name, ":"); j > strings.LastIndex(name, "/") {
+			name = name[:j]
+		}
+	} else if i = strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, reference = name[:i], name[i+1:]
+	}
+
+	ref := imageRef{registry: dockerHub, repository: name, reference: reference}
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry, ref.repository = first, rest
+	}
+	if ref.registry == dockerHub && !strings.Contains(ref.repository, "/") {
+		ref.repository = "library/" + ref.repository
+	}
+
+	if !repositoryPattern.MatchString(ref.repository) ||
+		!tagPattern.MatchString(ref.reference) && !digestPattern.MatchString(ref.reference) {
+		return imageRef{}, fmt.Errorf("invalid image %q: should look like %q", image, "envoyproxy/envoy:v"+version.LastKnownEnvoy.String())
+	}
+	return ref, nil
+}
+
+// url returns the URL of the registry API for the repository, such as "https://registry-1.docker.io/v2/library/x/".
+// Like Docker, a registry on the loopback interface is assumed to use plain HTTP.
+func (r imageRef) url(subpath string) string {
+	host, scheme := r.registry, "https"
+	if host == dockerHub {
+		host = dockerHubAPIHost
+	}
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	if ip := net.ParseIP(hostname); hostname == "localhost" || ip != nil && ip.IsLoopback() {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme, host, r.repository, subpath)
+}
+
+// ociDescriptor describes content in a registry, such as a manifest in an index, or a layer in a manifest.
+type ociDescriptor struct {
+	MediaType string       `json:"mediaType"`
+	Digest    string       `json:"digest"`
+	Size      int64        `json:"size"`
+	Platform  *ociPlatform `json:"platform,omitempty"`
+}
+
+type ociPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+// ociManifest is an image index, when it has Manifests, or an image manifest, when it has Layers.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Manifests []ociDescriptor `json:"manifests"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// registryClient pulls from an OCI distribution registry, authenticating with an anonymous token when challenged.
+type registryClient struct {
+	o     *globals.GlobalOpts
+	ref   imageRef
+	token string
+}
+
+// pullEnvoyImage extracts the Envoy binary of o.Platform from image into dst, with the same layout as an archive:
+// "bin/envoy". The digest of each layer is verified, as well as the digest of each manifest pulled by digest.
+func pullEnvoyImage(ctx context.Context, o *globals.GlobalOpts, dst string, image version.Image) error { // dst, src order like io.Copy
+	ref, err := parseImageRef(image)
+	if err != nil {
+		return err
+	}
+	if o.Offline {
+		return fmt.Errorf("offline, but %s is an image: install it with \"func-e install --from-file\", or run func-e without --offline", image)
+	}
+	c := &registryClient{o: o, ref: ref}
+
+	o.Logf("pulling %s for %s\n", image, o.Platform)
+	m, err := c.manifest(ctx, ref.reference)
+	if err != nil {
+		return err
+	}
+	if len(m.Manifests) > 0 {
+		d := m.platformManifest(o.Platform)
+		if d == nil {
+			return fmt.Errorf("image %s has no manifest for platform %q", image, o.Platform)
+		}
+		if m, err = c.manifest(ctx, d.Digest); err != nil {
+			return err
+		}
+	}
+	if len(m.Layers) == 0 {
+		return fmt.Errorf("image %s has no layers", image)
+	}
+
+	envoyPath := filepath.Join(dst, binEnvoy)
+	if err = os.MkdirAll(filepath.Dir(envoyPath), 0o750); err != nil {
+		return err
+	}
+	found := false
+	for _, l := range m.Layers { // apply layers in order, as a later one may replace or delete Envoy
+		if found, err = c.extractEnvoy(ctx, l, envoyPath, found); err != nil {
+			return fmt.Errorf("error pulling %s: %w", image, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("image %s has no /%s", image, imageEnvoyPath)
+	}
+	return nil
+}
+
+// platformManifest returns the descriptor of the manifest for p in an image index, or nil if there is none.
+func (m *ociManifest) platformManifest(p version.Platform) *ociDescriptor {
+	for i := range m.Manifests {
+		d := &m.Manifests[i]
+		if d.Platform != nil && version.Platform(d.Platform.OS+"/"+d.Platform.Architecture) == p {
+			return d
+		}
+	}
+	return nil
+}
+
+// manifest returns the manifest or image index for the tag or digest reference.
+func (c *registryClient) manifest(ctx context.Context, reference string) (*ociManifest, error) {
+	url := c.ref.url("manifests/" + reference)
+	res, err := c.get(ctx, url, http.Header{"Accept": {manifestAccept}})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close() //nolint:errcheck // read below
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received %v status code from %s", res.StatusCode, url)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxManifestSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", url, err)
+	}
+	if digestPattern.MatchString(reference) {
+		if err = verifyDigest(reference, sha256Hex(body)); err != nil {
+			return nil, fmt.Errorf("error verifying %s: %w", url, err)
+		}
+	}
+
+	var m ociManifest
+	if err = json.Unmarshal(body, &m); err != nil {
+		return nil, fmt.Errorf("error unmarshalling %s: %w", url, err)
+	}
+	if m.MediaType == "" { // optional in OCI, so use the response header
+		m.MediaType = res.Header.Get("Content-Type")
+	}
+	switch m.MediaType {
+	case mediaTypeOCIIndex, mediaTypeDockerList, mediaTypeOCIManifest, mediaTypeDockerManifest:
+		return &m, nil
+	default:
+		return nil, fmt.Errorf("unsupported media type %q of %s", m.MediaType, url)
+	}
+}
+
+// extractEnvoy applies the layer l to envoyPath, given whether a lower layer had Envoy. It returns whether Envoy is
+// there after this layer, which may add, replace or delete it.
+func (c *registryClient) extractEnvoy(ctx context.Context, l ociDescriptor, envoyPath string, found bool) (bool, error) {
+	extracted := false // a whiteout only deletes Envoy from lower layers
+	linkname := ""     // set when Envoy is a hard link to a file earlier in this layer
+	err := c.readLayer(ctx, l, func(hdr *archivetar.Header, r io.Reader) error {
+		name := cleanLayerPath(hdr.Name)
+		switch {
+		case name == imageEnvoyPath:
+			switch hdr.Typeflag {
+			case archivetar.TypeReg:
+				if err := writeEnvoy(envoyPath, r); err != nil {
+					return err
+				}
+				linkname = ""
+			case archivetar.TypeLink:
+				linkname = cleanLayerPath(hdr.Linkname)
+			default:
+				return fmt.Errorf("/%s in layer %s isn't a regular file or a hard link", imageEnvoyPath, l.Digest)
+			}
+			found, extracted = true, true
+		case isEnvoyWhiteout(name):
+			if found && !extracted {
+				if err := os.Remove(envoyPath); err != nil {
+					return err
+				}
+				found = false
+			}
+		}
+		return nil
+	})
+	if err != nil || linkname == "" {
+		return found, err
+	}
+
+	// The target was already read, so read the layer again to extract it.
+	linked := false
+	err = c.readLayer(ctx, l, func(hdr *archivetar.Header, r io.Reader) error {
+		if linked || cleanLayerPath(hdr.Name) != linkname || hdr.Typeflag != archivetar.TypeReg {
+			return nil
+		}
+		linked = true
+		return writeEnvoy(envoyPath, r)
+	})
+	if err == nil && !linked {
+		err = fmt.Errorf("/%s in layer %s is a hard link to /%s, which isn't a regular file in that layer", imageEnvoyPath, l.Digest, linkname)
+	}
+	return found, err
+}
+
+// readLayer calls fn with each entry of the layer l, then verifies its digest.
+func (c *registryClient) readLayer(ctx context.Context, l ociDescriptor, fn func(hdr *archivetar.Header, r io.Reader) error) error {
+	if !digestPattern.MatchString(l.Digest) {
+		return fmt.Errorf("unsupported layer digest %q", l.Digest)
+	}
+	var gzipped bool
+	switch l.MediaType {
+	case mediaTypeOCILayerGzip, mediaTypeDockerLayerGzip:
+		gzipped = true
+	case mediaTypeOCILayer:
+	default:
+		return fmt.Errorf("unsupported media type %q of layer %s", l.MediaType, l.Digest)
+	}
+
+	url := c.ref.url("blobs/" + l.Digest)
+	res, err := c.get(ctx, url, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close() //nolint:errcheck // read below
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("received %v status code from %s", res.StatusCode, url)
+	}
+
+	// Hash everything read, so the digest covers the whole layer, not only what the tar reader needed.
+	h := sha256.New()
+	blob := io.TeeReader(res.Body, h)
+	src := blob
+	if gzipped {
+		zr, err := gzip.NewReader(blob)
+		if err != nil {
+			return fmt.Errorf("error reading layer %s: %w", l.Digest, err)
+		}
+		src = zr
+	}
+
+	tr := archivetar.NewReader(src)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading layer %s: %w", l.Digest, err)
+		}
+		if err = fn(hdr, tr); err != nil {
+			return err
+		}
+	}
+	if _, err = io.Copy(io.Discard, blob); err != nil { // such as padding after the end of the tar
+		return fmt.Errorf("error reading layer %s: %w", l.Digest, err)
+	}
+	if err = verifyDigest(l.Digest, hex.EncodeToString(h.Sum(nil))); err != nil {
+		return fmt.Errorf("error verifying layer: %w", err)
+	}
+	return nil
+}
+
+// cleanLayerPath returns the path of a layer entry or hard link target relative to the root, like imageEnvoyPath.
+func cleanLayerPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// isEnvoyWhiteout returns true if name deletes imageEnvoyPath from lower layers: a whiteout of it or one of its parent
+// directories, or an opaque whiteout of one of those directories.
+func isEnvoyWhiteout(name string) bool {
+	for p := imageEnvoyPath; p != "."; p = path.Dir(p) {
+		dir, base := path.Split(p)
+		if name == dir+whiteoutPrefix+base || name == dir+opaqueWhiteout {
+			return true
+		}
+	}
+	return false
+}
+
+func writeEnvoy(envoyPath string, src io.Reader) error {
+	f, err := os.OpenFile(envoyPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755) //nolint:gosec // envoyPath is in DataHome
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, src); err != nil { //nolint:gosec // the layer is verified by its digest
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// get GETs url with the given header, retrying once with an anonymous Bearer token if the registry challenges it.
+// The token is reused for later requests.
+func (c *registryClient) get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := httpGetWithHeader(ctx, c.o.HTTPClient, url, c.o.UserAgent, header)
+	if err != nil || res.StatusCode != http.StatusUnauthorized || c.token != "" {
+		return res, err
+	}
+	challenge := res.Header.Get("WWW-Authenticate")
+	_ = res.Body.Close()
+	if c.token, err = c.authenticate(ctx, challenge); err != nil {
+		return nil, err
+	}
+	header.Set("Authorization", "Bearer "+c.token)
+	return httpGetWithHeader(ctx, c.o.HTTPClient, url, c.o.UserAgent, header)
+}
+
+// authenticate gets an anonymous token to pull the repository, from the realm of a challenge like:
+// `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:envoyproxy/envoy:pull"`
+func (c *registryClient) authenticate(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("registry %s requires unsupported authentication %q", c.ref.registry, scheme)
+	}
+	p := map[string]string{}
+	for _, m := range challengeParamPattern.FindAllStringSubmatch(params, -1) {
+		p[m[1]] = m[2]
+	}
+	realm, err := neturl.Parse(p["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("registry %s has an invalid token realm %q", c.ref.registry, p["realm"])
+	}
+	if p["scope"] == "" {
+		p["scope"] = "repository:" + c.ref.repository + ":pull"
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if p[k] != "" {
+			q.Set(k, p[k])
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	res, err := httpGet(ctx, c.o.HTTPClient, realm.String(), c.o.UserAgent)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close() //nolint:errcheck // read below
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("received %v status code from %s", res.StatusCode, realm)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(io.LimitReader(res.Body, maxManifestSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("error unmarshalling token from %s: %w", realm, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("missing token from %s", realm)
+	}
+	return token.Token, nil
+}
+
+func verifyDigest(digest, sha256Sum string) error {
+	if actual := "sha256:" + sha256Sum; actual != digest {
+		return fmt.Errorf("expected digest %q, but have %q", digest, actual)
+	}
+	return nil
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
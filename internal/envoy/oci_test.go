@@ -0,0 +1,358 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/globals"
+	"github.com/tetratelabs/func-e/internal/test"
+	"github.com/tetratelabs/func-e/internal/test/httptest"
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+const testImage = version.Image("registry.example.com/envoyproxy/envoy:v1.31.2")
+
+func TestParseImageRef(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+
+	tests := []struct {
+		image       version.Image
+		expected    imageRef
+		expectedURL string
+		expectedErr string
+	}{
+		{
+			image:       "envoyproxy/envoy:v1.31.2",
+			expected:    imageRef{"docker.io", "envoyproxy/envoy", "v1.31.2"},
+			expectedURL: "https://registry-1.docker.io/v2/envoyproxy/envoy/manifests/v1.31.2",
+		},
+		{
+			image:       "envoy",
+			expected:    imageRef{"docker.io", "library/envoy", "latest"},
+			expectedURL: "https://registry-1.docker.io/v2/library/envoy/manifests/latest",
+		},
+		{
+			image:       "registry.example.com/team/envoy@" + version.Image(digest),
+			expected:    imageRef{"registry.example.com", "team/envoy", digest},
+			expectedURL: "https://registry.example.com/v2/team/envoy/manifests/" + digest,
+		},
+		{
+			image:       "localhost:5000/envoy:v1.31.2@" + version.Image(digest),
+			expected:    imageRef{"localhost:5000", "envoy", digest},
+			expectedURL: "http://localhost:5000/v2/envoy/manifests/" + digest,
+		},
+		{
+			image:       "127.0.0.1:5000/envoy",
+			expected:    imageRef{"127.0.0.1:5000", "envoy", "latest"},
+			expectedURL: "http://127.0.0.1:5000/v2/envoy/manifests/latest",
+		},
+		{image: "Envoy", expectedErr: `invalid image "Envoy": should look like "envoyproxy/envoy:v` + version.LastKnownEnvoy.String() + `"`},
+		{image: "envoy@sha256:abc", expectedErr: `invalid image "envoy@sha256:abc": should look like "envoyproxy/envoy:v` + version.LastKnownEnvoy.String() + `"`},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.image), func(t *testing.T) {
+			ref, err := parseImageRef(tc.image)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, ref)
+			require.Equal(t, tc.expectedURL, ref.url("manifests/"+ref.reference))
+		})
+	}
+}
+
+func TestPullEnvoyImage(t *testing.T) {
+	envoys := map[version.Platform][]byte{"linux/amd64": []byte("amd64 envoy"), "linux/arm64": []byte("arm64 envoy")}
+
+	tests := []struct {
+		name          string
+		image         version.Image
+		platform      version.Platform
+		tamper        func(r *test.FakeRegistry)
+		expectedEnvoy string
+		expectedErr   string
+	}{
+		{name: "amd64", image: testImage, platform: "linux/amd64", expectedEnvoy: "amd64 envoy"},
+		{name: "arm64", image: testImage, platform: "linux/arm64", expectedEnvoy: "arm64 envoy"},
+		{
+			name:        "no platform",
+			image:       testImage,
+			platform:    "darwin/arm64",
+			expectedErr: fmt.Sprintf(`image %s has no manifest for platform "darwin/arm64"`, testImage),
+		},
+		{
+			name:        "not found",
+			image:       "registry.example.com/envoyproxy/envoy:v1.0.0",
+			platform:    "linux/amd64",
+			expectedErr: "received 404 status code from https://registry.example.com/v2/envoyproxy/envoy/manifests/v1.0.0",
+		},
+		{
+			name:     "tampered layer",
+			image:    testImage,
+			platform: "linux/amd64",
+			tamper: func(r *test.FakeRegistry) {
+				for d := range r.Blobs {
+					r.Blobs[d] = bytes.Replace(r.Blobs[d], []byte("amd64 envoy"), []byte("evil! envoy"), 1)
+				}
+			},
+			expectedErr: fmt.Sprintf(`error pulling %s: error verifying layer: expected digest "sha256:`, testImage),
+		},
+		{
+			name:     "tampered manifest",
+			image:    testImage,
+			platform: "linux/amd64",
+			tamper: func(r *test.FakeRegistry) {
+				for ref, m := range r.Manifests {
+					if strings.HasPrefix(ref, "sha256:") && ref != r.Digest("v1.31.2") {
+						r.Manifests[ref] = append(m, ' ')
+					}
+				}
+			},
+			expectedErr: "error verifying https://registry.example.com/v2/envoyproxy/envoy/manifests/sha256:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2", envoys)
+			if tc.tamper != nil {
+				tc.tamper(r)
+			}
+			o := &globals.GlobalOpts{Platform: tc.platform, Quiet: true}
+			o.HTTPClient = httptest.HTTPClient(r)
+			dst := t.TempDir()
+
+			err := pullEnvoyImage(t.Context(), o, dst, tc.image)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			requireEnvoy(t, dst, tc.expectedEnvoy)
+		})
+	}
+}
+
+func TestPullEnvoyImage_ByDigest(t *testing.T) {
+	r := test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2", map[version.Platform][]byte{"linux/amd64": []byte("envoy")})
+	o := &globals.GlobalOpts{Platform: "linux/amd64", Quiet: true}
+	o.HTTPClient = httptest.HTTPClient(r)
+	image := version.Image("registry.example.com/envoyproxy/envoy@" + r.Digest("v1.31.2"))
+
+	dst := t.TempDir()
+	require.NoError(t, pullEnvoyImage(t.Context(), o, dst, image))
+	requireEnvoy(t, dst, "envoy")
+
+	// The index is verified as well, when pinned by digest.
+	r.Manifests[r.Digest("v1.31.2")] = append(r.Manifests["v1.31.2"], ' ')
+	err := pullEnvoyImage(t.Context(), o, t.TempDir(), image)
+	require.ErrorContains(t, err, "error verifying https://registry.example.com/v2/envoyproxy/envoy/manifests/"+r.Digest("v1.31.2"))
+}
+
+func TestPullEnvoyImage_Whiteout(t *testing.T) {
+	tests := []struct {
+		name     string
+		whiteout string
+		expected string // empty when Envoy is deleted
+	}{
+		{name: "file", whiteout: "usr/local/bin/.wh.envoy"},
+		{name: "opaque", whiteout: "usr/local/bin/.wh..wh..opq"},
+		{name: "parent directory", whiteout: "usr/local/.wh.bin"},
+		{name: "root directory", whiteout: ".wh.usr"},
+		{name: "opaque parent directory", whiteout: "usr/.wh..wh..opq"},
+		{name: "other file", whiteout: "usr/local/bin/.wh.envoy-other", expected: "envoy"},
+		{name: "other directory", whiteout: "usr/local/.wh.lib", expected: "envoy"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2", map[version.Platform][]byte{"linux/amd64": []byte("envoy")})
+			addLayer(t, r, "v1.31.2", requireTar(t, tc.whiteout))
+			o := &globals.GlobalOpts{Platform: "linux/amd64", Quiet: true}
+			o.HTTPClient = httptest.HTTPClient(r)
+			dst := t.TempDir()
+
+			err := pullEnvoyImage(t.Context(), o, dst, testImage)
+			if tc.expected == "" {
+				require.EqualError(t, err, fmt.Sprintf("image %s has no /usr/local/bin/envoy", testImage))
+				return
+			}
+			require.NoError(t, err)
+			requireEnvoy(t, dst, tc.expected)
+		})
+	}
+}
+
+func TestPullEnvoyImage_Link(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []tarEntry
+		expectedErr string
+	}{
+		{
+			name: "hard link",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeReg, Name: "usr/local/bin/envoy-1.31.2"}, content: "linked envoy"},
+				{header: tar.Header{Typeflag: tar.TypeLink, Name: "usr/local/bin/envoy", Linkname: "/usr/local/bin/envoy-1.31.2"}},
+			},
+		},
+		{
+			name: "hard link to missing file",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeLink, Name: "usr/local/bin/envoy", Linkname: "usr/bin/envoy"}},
+			},
+			expectedErr: "/usr/local/bin/envoy in layer $DIGEST is a hard link to /usr/bin/envoy, which isn't a regular file in that layer",
+		},
+		{
+			name: "symbolic link",
+			entries: []tarEntry{
+				{header: tar.Header{Typeflag: tar.TypeSymlink, Name: "usr/local/bin/envoy", Linkname: "envoy-1.31.2"}},
+			},
+			expectedErr: "/usr/local/bin/envoy in layer $DIGEST isn't a regular file or a hard link",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2", map[version.Platform][]byte{"linux/amd64": []byte("envoy")})
+			layer := requireTarEntries(t, tc.entries...)
+			addLayer(t, r, "v1.31.2", layer)
+			o := &globals.GlobalOpts{Platform: "linux/amd64", Quiet: true}
+			o.HTTPClient = httptest.HTTPClient(r)
+			dst := t.TempDir()
+
+			err := pullEnvoyImage(t.Context(), o, dst, testImage)
+			if tc.expectedErr != "" {
+				expectedErr := strings.ReplaceAll(tc.expectedErr, "$DIGEST", "sha256:"+sha256Hex(layer))
+				require.EqualError(t, err, fmt.Sprintf("error pulling %s: %s", testImage, expectedErr))
+				return
+			}
+			require.NoError(t, err)
+			requireEnvoy(t, dst, "linked envoy")
+		})
+	}
+}
+
+func TestPullEnvoyImage_Offline(t *testing.T) {
+	o := &globals.GlobalOpts{Platform: "linux/amd64", Offline: true, Quiet: true}
+	err := pullEnvoyImage(t.Context(), o, t.TempDir(), testImage)
+	require.EqualError(t, err, fmt.Sprintf(`offline, but %s is an image: install it with "func-e install --from-file", or run func-e without --offline`, testImage))
+}
+
+func TestInstallIfNeeded_Image(t *testing.T) {
+	r := test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2", map[version.Platform][]byte{"linux/amd64": []byte("envoy")})
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Platform: "linux/amd64", EnvoyVersion: "1.31.2", Out: new(bytes.Buffer)}
+	o.HTTPClient = httptest.HTTPClient(r)
+	o.GetEnvoyVersions = func(context.Context) (*version.ReleaseVersions, error) {
+		return &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+			"1.31.2": {ReleaseDate: "2024-09-19", Image: testImage},
+		}}, nil
+	}
+
+	envoyPath, err := InstallIfNeeded(t.Context(), o)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(o.EnvoyVersionsDir(), "1.31.2", binEnvoy), envoyPath)
+	requireEnvoy(t, filepath.Join(o.EnvoyVersionsDir(), "1.31.2"), "envoy")
+	require.Equal(t, fmt.Sprintf("pulling %s for linux/amd64\n", testImage), o.Out.(*bytes.Buffer).String())
+
+	stat, err := os.Stat(filepath.Join(o.EnvoyVersionsDir(), "1.31.2"))
+	require.NoError(t, err)
+	require.Equal(t, "2024-09-19", stat.ModTime().UTC().Format("2006-01-02"))
+}
+
+func TestInstallFromImage(t *testing.T) {
+	r := test.NewFakeRegistry(t, "envoyproxy/envoy", "v1.31.2", map[version.Platform][]byte{"linux/amd64": []byte("envoy")})
+	o := &globals.GlobalOpts{DataHome: t.TempDir(), Platform: "linux/amd64", Quiet: true}
+	o.HTTPClient = httptest.HTTPClient(r)
+
+	envoyPath, err := InstallFromImage(t.Context(), o, "1.31.2", testImage, time.Now())
+	require.NoError(t, err)
+	require.FileExists(t, envoyPath)
+	pulled := len(r.Pulled)
+
+	// Already installed, so nothing is pulled.
+	_, err = InstallFromImage(t.Context(), o, "1.31.2", testImage, time.Now())
+	require.NoError(t, err)
+	require.Len(t, r.Pulled, pulled)
+}
+
+func requireEnvoy(t *testing.T, installPath, expected string) {
+	t.Helper()
+	envoyPath := filepath.Join(installPath, binEnvoy)
+	b, err := os.ReadFile(envoyPath)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(b))
+	_, err = verifyEnvoy(installPath)
+	require.NoError(t, err)
+}
+
+// requireTar returns an uncompressed layer of empty files, such as whiteouts.
+func requireTar(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var entries []tarEntry
+	for _, name := range names {
+		entries = append(entries, tarEntry{header: tar.Header{Typeflag: tar.TypeReg, Name: name}})
+	}
+	return requireTarEntries(t, entries...)
+}
+
+// tarEntry is an entry of a layer written by requireTarEntries, with the content of a regular file.
+type tarEntry struct {
+	header  tar.Header
+	content string
+}
+
+// requireTarEntries returns an uncompressed layer of the entries, in order.
+func requireTarEntries(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		hdr := e.header
+		hdr.Mode, hdr.Size = 0o755, int64(len(e.content))
+		require.NoError(t, tw.WriteHeader(&hdr))
+		_, err := tw.Write([]byte(e.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
+
+// addLayer adds the uncompressed layer to the top of each manifest in the index tagged tag.
+func addLayer(t *testing.T, r *test.FakeRegistry, tag string, layer []byte) {
+	t.Helper()
+	layerDigest := "sha256:" + sha256Hex(layer)
+	r.Blobs[layerDigest] = layer
+
+	var index map[string]any
+	require.NoError(t, json.Unmarshal(r.Manifests[tag], &index))
+	for _, d := range index["manifests"].([]any) {
+		d := d.(map[string]any)
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(r.Manifests[d["digest"].(string)], &m))
+		m["layers"] = append(m["layers"].([]any), map[string]any{"mediaType": mediaTypeOCILayer, "digest": layerDigest, "size": len(layer)})
+		b, err := json.Marshal(m)
+		require.NoError(t, err)
+		d["digest"], d["size"] = "sha256:"+sha256Hex(b), len(b)
+		r.Manifests[d["digest"].(string)] = b
+	}
+	b, err := json.Marshal(index)
+	require.NoError(t, err)
+	r.Manifests[tag] = b
+}
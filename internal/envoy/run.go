@@ -42,8 +42,10 @@ func (r *Runtime) Run(ctx context.Context, args []string) error {
 	// Print the binary and state directory to the user for debugging purposes.
 	r.logf("starting: %s with logs in %s", r.o.EnvoyPath, r.o.RunDir)
 	if err := cmd.Start(); err != nil {
+		r.updateRunInfo(func(info *RunInfo) { info.EndTime = now() })
 		return fmt.Errorf("unable to start Envoy process: %w", err)
 	}
+	r.updateRunInfo(func(info *RunInfo) { info.Pid = cmd.Process.Pid })
 
 	hookErrCh := make(chan error, 1)
 
@@ -86,6 +88,11 @@ func (r *Runtime) Run(ctx context.Context, args []string) error {
 	// Wait for the process, and any admin monitoring, to complete
 	exitErr := cmd.Wait()
 	cancelMonitor() // Stop monitoring immediately when process exits
+	r.updateRunInfo(func(info *RunInfo) {
+		info.EndTime = now()
+		exitStatus := cmd.ProcessState.ExitCode()
+		info.ExitStatus = &exitStatus
+	})
 	hookErr := <-hookErrCh
 
 	// Prioritize hook errors - if the hook ran and failed, that's the most relevant error
@@ -99,3 +106,20 @@ func (r *Runtime) Run(ctx context.Context, args []string) error {
 	}
 	return exitErr
 }
+
+// updateRunInfo applies the update to Runtime.RunInfo, if set, and persists it.
+// Failures are only logged, as run metadata shouldn't interfere with running Envoy.
+func (r *Runtime) updateRunInfo(update func(info *RunInfo)) {
+	if r.RunInfo == nil {
+		return
+	}
+	update(r.RunInfo)
+	if err := WriteRunInfo(r.o.RunDir, r.RunInfo); err != nil {
+		r.logf("failed to update run info: %s", err)
+	}
+}
+
+func now() *time.Time {
+	t := time.Now()
+	return &t
+}
@@ -42,6 +42,7 @@ func TestRuntime_Run_EnvoyError(t *testing.T) {
 		RunID:      "test-run-id",
 	}, logToOutput)
 	r.Out, r.Err = stdout, stderr
+	r.RunInfo = &RunInfo{RunID: "test-run-id", StartTime: time.Now()}
 
 	// Envoy with invalid config is expected to fail
 	err := r.Run(t.Context(), []string{"--config-yaml", "invalid.yaml"})
@@ -70,6 +71,15 @@ func TestRuntime_Run_EnvoyError(t *testing.T) {
 		require.NoError(t, err, "run directory should exist")
 		require.True(t, info.IsDir(), "run directory should be a directory")
 	})
+
+	t.Run("run info records exit", func(t *testing.T) {
+		info, err := ReadRunInfo(runDir)
+		require.NoError(t, err)
+		require.Equal(t, r.cmd.Process.Pid, info.Pid)
+		require.NotNil(t, info.EndTime)
+		require.NotNil(t, info.ExitStatus)
+		require.Equal(t, 1, *info.ExitStatus)
+	})
 }
 
 func TestRuntime_Run_StartupHook(t *testing.T) {
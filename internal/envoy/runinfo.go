@@ -0,0 +1,76 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RunInfoFile is the name of the file in globals.RunOpts RunDir that records RunInfo.
+const RunInfoFile = "run.json"
+
+// RunInfo is metadata about a "func-e run", written when it starts and updated when Envoy exits.
+type RunInfo struct {
+	// RunID is the globals.RunOpts RunID of the run.
+	RunID string `json:"runID"`
+	// StartTime is when func-e started Envoy.
+	StartTime time.Time `json:"startTime"`
+	// EndTime is when Envoy exited, or nil if it hasn't, yet.
+	EndTime *time.Time `json:"endTime,omitempty"`
+	// EnvoyVersion is the version of Envoy, or empty when run with a custom Envoy path.
+	EnvoyVersion string `json:"envoyVersion,omitempty"`
+	// EnvoyPath is the path to the Envoy binary.
+	EnvoyPath string `json:"envoyPath"`
+	// Args are the arguments passed to "func-e run".
+	Args []string `json:"args"`
+	// Pid is the process ID of Envoy, or zero if it didn't start.
+	Pid int `json:"pid,omitempty"`
+	// ExitStatus is the exit status of Envoy, or nil if it hasn't exited, yet.
+	ExitStatus *int `json:"exitStatus,omitempty"`
+}
+
+// Duration returns how long Envoy ran, or zero if it hasn't exited, yet.
+func (i *RunInfo) Duration() time.Duration {
+	if i.EndTime == nil {
+		return 0
+	}
+	return i.EndTime.Sub(i.StartTime)
+}
+
+// WriteRunInfo writes RunInfoFile into runDir.
+//
+// The file is replaced atomically, so concurrent readers never see a partial write.
+func WriteRunInfo(runDir string, info *RunInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(runDir, RunInfoFile)
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("couldn't write %s: %w", path, err)
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("couldn't write %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadRunInfo reads RunInfoFile from runDir.
+func ReadRunInfo(runDir string) (*RunInfo, error) {
+	path := filepath.Join(runDir, RunInfoFile)
+	data, err := os.ReadFile(path) //nolint:gosec // runDir is configured by us, not user input
+	if err != nil {
+		return nil, err
+	}
+	info := &RunInfo{}
+	if err = json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("couldn't read %s: %w", path, err)
+	}
+	return info, nil
+}
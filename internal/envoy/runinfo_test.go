@@ -0,0 +1,55 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRunInfo_ReadRunInfo(t *testing.T) {
+	runDir := t.TempDir()
+	start := time.Date(2025, 10, 12, 14, 30, 53, 0, time.UTC)
+	end := start.Add(90 * time.Second)
+	exitStatus := 0
+	expected := &RunInfo{
+		RunID:        "20251012_143053_700",
+		StartTime:    start,
+		EndTime:      &end,
+		EnvoyVersion: "1.35.0",
+		EnvoyPath:    "/opt/envoy",
+		Args:         []string{"-c", "envoy.yaml"},
+		Pid:          1234,
+		ExitStatus:   &exitStatus,
+	}
+
+	require.NoError(t, WriteRunInfo(runDir, expected))
+	require.NoFileExists(t, filepath.Join(runDir, RunInfoFile+".tmp"))
+
+	actual, err := ReadRunInfo(runDir)
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+	require.Equal(t, 90*time.Second, actual.Duration())
+}
+
+func TestRunInfo_DurationWhileRunning(t *testing.T) {
+	info := &RunInfo{StartTime: time.Now()}
+	require.Zero(t, info.Duration())
+}
+
+func TestReadRunInfo_Invalid(t *testing.T) {
+	runDir := t.TempDir()
+
+	_, err := ReadRunInfo(runDir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(runDir, RunInfoFile)
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = ReadRunInfo(runDir)
+	require.EqualError(t, err, "couldn't read "+path+": unexpected end of JSON input")
+}
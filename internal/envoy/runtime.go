@@ -58,6 +58,8 @@ type Runtime struct {
 	cmd              *exec.Cmd
 	Out, Err         io.Writer
 	OutFile, ErrFile *os.File
+	// RunInfo, when set, is updated in RunInfoFile with the Envoy pid once started, and its exit status when done.
+	RunInfo *RunInfo
 
 	logf LogFunc
 
@@ -0,0 +1,142 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// signatureSuffix is appended to the Envoy versions URL to get its detached signature.
+const signatureSuffix = ".sig"
+
+const (
+	untrustedCommentPrefix = "untrusted comment: "
+	trustedCommentPrefix   = "trusted comment: "
+	// algEd25519 is the minisign algorithm that signs the content. "ED", which signs a BLAKE2b hash of it,
+	// isn't supported as the standard library has no BLAKE2b.
+	algEd25519 = "Ed"
+)
+
+// PublicKey is an Ed25519 public key in minisign format, trusted to sign the Envoy versions JSON.
+type PublicKey struct {
+	// ID is the key ID, which minisign prints like "D8C3C6CAF23D1A9B".
+	ID  uint64
+	key ed25519.PublicKey
+}
+
+// ParsePublicKey parses a minisign public key, such as the contents of "minisign.pub". The "untrusted comment"
+// line is optional.
+func ParsePublicKey(s string) (PublicKey, error) {
+	b, err := decodeMinisignLine(s)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(b) != 2+8+ed25519.PublicKeySize || string(b[:2]) != algEd25519 {
+		return PublicKey{}, errors.New("invalid public key: not an Ed25519 minisign key")
+	}
+	return PublicKey{ID: binary.LittleEndian.Uint64(b[2:10]), key: ed25519.PublicKey(b[10:])}, nil
+}
+
+// LoadTrustedKeys returns the public keys in the "*.pub" files of dir, which may not exist, and the given keys.
+func LoadTrustedKeys(dir string, keys []string) ([]PublicKey, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pub"))
+	if err != nil {
+		return nil, err
+	}
+	var result []PublicKey
+	for _, f := range files {
+		b, err := os.ReadFile(f) //nolint:gosec // f is in ConfigHome
+		if err != nil {
+			return nil, err
+		}
+		k, err := ParsePublicKey(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		result = append(result, k)
+	}
+	for _, s := range keys {
+		k, err := ParsePublicKey(s)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, k)
+	}
+	return result, nil
+}
+
+// SignatureVerifier verifies the detached signature of the Envoy versions JSON, read from its URL plus ".sig".
+type SignatureVerifier struct {
+	// Keys are trusted to sign the Envoy versions JSON.
+	Keys []PublicKey
+	// Required means an unsigned Envoy versions JSON is an error. Otherwise, only an invalid signature is.
+	Required bool
+}
+
+// verify returns an error unless sig is a valid minisign signature of body from a trusted key. sig is nil when the
+// Envoy versions JSON at url isn't signed.
+func (v *SignatureVerifier) verify(url string, body, sig []byte) error {
+	if sig == nil {
+		if v.Required {
+			return fmt.Errorf("signature required, but %s has no signature at %s", url, url+signatureSuffix)
+		}
+		return nil
+	}
+	if len(v.Keys) == 0 {
+		return fmt.Errorf("signature required, but there are no trusted keys to verify %s", url+signatureSuffix)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(sig)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return fmt.Errorf("invalid signature %s: not in minisign format", url+signatureSuffix)
+	}
+	s, err := decodeMinisignLine(lines[0] + "\n" + lines[1])
+	if err != nil || len(s) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature %s: not in minisign format", url+signatureSuffix)
+	}
+	if alg := string(s[:2]); alg != algEd25519 {
+		return fmt.Errorf("invalid signature %s: algorithm %q isn't supported: sign with \"minisign -S -l\"", url+signatureSuffix, alg)
+	}
+	id := binary.LittleEndian.Uint64(s[2:10])
+	var key ed25519.PublicKey
+	for _, k := range v.Keys {
+		if k.ID == id {
+			key = k.key
+		}
+	}
+	if key == nil {
+		return fmt.Errorf("%s is signed by key %016X, which isn't trusted", url, id)
+	}
+	if !ed25519.Verify(key, body, s[10:]) {
+		return fmt.Errorf("%s doesn't match its signature from key %016X", url, id)
+	}
+
+	// The trusted comment is signed along with the signature, so it can't be changed either.
+	trustedComment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), trustedCommentPrefix)
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || !ed25519.Verify(key, append(bytes.Clone(s[10:]), trustedComment...), globalSig) {
+		return fmt.Errorf("invalid signature %s: the trusted comment doesn't match", url+signatureSuffix)
+	}
+	return nil
+}
+
+// decodeMinisignLine decodes the base64 line of a minisign key or signature, skipping any untrusted comment.
+func decodeMinisignLine(s string) ([]byte, error) {
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, untrustedCommentPrefix) {
+			continue
+		}
+		return base64.StdEncoding.DecodeString(line)
+	}
+	return nil, errors.New("missing base64 line")
+}
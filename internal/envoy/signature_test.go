@@ -0,0 +1,182 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testVersionsURL = "https://example.com/envoy-versions.json"
+
+func TestParsePublicKey(t *testing.T) {
+	signer := newTestSigner(t, 1)
+
+	tests := []struct {
+		name        string
+		input       string
+		expectedErr string
+	}{
+		{name: "minisign.pub", input: signer.publicKey()},
+		{name: "without comment", input: strings.Split(signer.publicKey(), "\n")[1]},
+		{name: "empty", expectedErr: "invalid public key: missing base64 line"},
+		{name: "not base64", input: "untrusted comment: x\n!!!", expectedErr: "invalid public key: illegal base64 data at input byte 0"},
+		{name: "not ed25519", input: base64.StdEncoding.EncodeToString([]byte("RSA")), expectedErr: "invalid public key: not an Ed25519 minisign key"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k, err := ParsePublicKey(tc.input)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, uint64(1), k.ID)
+			require.Equal(t, signer.pub, k.key)
+		})
+	}
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	one, two := newTestSigner(t, 1), newTestSigner(t, 2)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "one.pub"), []byte(one.publicKey()), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a key"), 0o600))
+
+	keys, err := LoadTrustedKeys(dir, []string{two.publicKey()})
+	require.NoError(t, err)
+	require.Equal(t, []PublicKey{{ID: 1, key: one.pub}, {ID: 2, key: two.pub}}, keys)
+
+	keys, err = LoadTrustedKeys(filepath.Join(dir, "missing"), nil)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.pub"), []byte("bad"), 0o600))
+	_, err = LoadTrustedKeys(dir, nil)
+	require.EqualError(t, err, filepath.Join(dir, "bad.pub")+": invalid public key: illegal base64 data at input byte 0")
+}
+
+func TestSignatureVerifier_Verify(t *testing.T) {
+	trusted, untrusted := newTestSigner(t, 0xD8C3C6CAF23D1A9B), newTestSigner(t, 2)
+	body := []byte(`{"versions":{}}`)
+	sig := trusted.sign(body, "timestamp:1700000000")
+	keys := []PublicKey{{ID: trusted.id, key: trusted.pub}}
+
+	tests := []struct {
+		name        string
+		sv          SignatureVerifier
+		body, sig   []byte
+		expectedErr string
+	}{
+		{name: "valid", sv: SignatureVerifier{Keys: keys, Required: true}, body: body, sig: sig},
+		{name: "unsigned", sv: SignatureVerifier{Keys: keys}, body: body},
+		{
+			name:        "unsigned required",
+			sv:          SignatureVerifier{Keys: keys, Required: true},
+			body:        body,
+			expectedErr: "signature required, but " + testVersionsURL + " has no signature at " + testVersionsURL + ".sig",
+		},
+		{
+			name:        "no trusted keys",
+			sv:          SignatureVerifier{Required: true},
+			body:        body,
+			sig:         sig,
+			expectedErr: "signature required, but there are no trusted keys to verify " + testVersionsURL + ".sig",
+		},
+		{
+			name:        "untrusted key",
+			sv:          SignatureVerifier{Keys: keys},
+			body:        body,
+			sig:         untrusted.sign(body, "timestamp:1700000000"),
+			expectedErr: testVersionsURL + " is signed by key 0000000000000002, which isn't trusted",
+		},
+		{
+			name:        "tampered body",
+			sv:          SignatureVerifier{Keys: keys},
+			body:        []byte(`{"versions":{"1.0.0":{}}}`),
+			sig:         sig,
+			expectedErr: testVersionsURL + " doesn't match its signature from key D8C3C6CAF23D1A9B",
+		},
+		{
+			name:        "tampered trusted comment",
+			sv:          SignatureVerifier{Keys: keys},
+			body:        body,
+			sig:         []byte(strings.Replace(string(sig), "timestamp:1700000000", "timestamp:1800000000", 1)),
+			expectedErr: "invalid signature " + testVersionsURL + ".sig: the trusted comment doesn't match",
+		},
+		{
+			name:        "prehashed",
+			sv:          SignatureVerifier{Keys: keys},
+			body:        body,
+			sig:         []byte(strings.Replace(string(sig), base64Line(sig, 1), base64.StdEncoding.EncodeToString(append([]byte("ED"), decodeLine(t, sig, 1)[2:]...)), 1)),
+			expectedErr: "invalid signature " + testVersionsURL + `.sig: algorithm "ED" isn't supported: sign with "minisign -S -l"`,
+		},
+		{
+			name:        "not minisign",
+			sv:          SignatureVerifier{Keys: keys},
+			body:        body,
+			sig:         []byte("-----BEGIN PGP SIGNATURE-----"),
+			expectedErr: "invalid signature " + testVersionsURL + ".sig: not in minisign format",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.sv.verify(testVersionsURL, tc.body, tc.sig)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+// testSigner creates minisign keys and legacy signatures, like "minisign -S -l".
+type testSigner struct {
+	id   uint64
+	pub  ed25519.PublicKey
+	priv ed25519.PrivateKey
+}
+
+func newTestSigner(t *testing.T, id uint64) *testSigner {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return &testSigner{id: id, pub: pub, priv: priv}
+}
+
+func (s *testSigner) publicKey() string {
+	b := append([]byte(algEd25519), binary.LittleEndian.AppendUint64(nil, s.id)...)
+	b = append(b, s.pub...)
+	return fmt.Sprintf("untrusted comment: minisign public key %016X\n%s\n", s.id, base64.StdEncoding.EncodeToString(b))
+}
+
+func (s *testSigner) sign(body []byte, trustedComment string) []byte {
+	sig := ed25519.Sign(s.priv, body)
+	b := append([]byte(algEd25519), binary.LittleEndian.AppendUint64(nil, s.id)...)
+	b = append(b, sig...)
+	globalSig := ed25519.Sign(s.priv, append(sig, trustedComment...))
+	return fmt.Appendf(nil, "untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(b), trustedComment, base64.StdEncoding.EncodeToString(globalSig))
+}
+
+func base64Line(sig []byte, i int) string {
+	return strings.Split(string(sig), "\n")[i]
+}
+
+func decodeLine(t *testing.T, sig []byte, i int) []byte {
+	b, err := base64.StdEncoding.DecodeString(base64Line(sig, i))
+	require.NoError(t, err)
+	return b
+}
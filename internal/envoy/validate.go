@@ -0,0 +1,31 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Validate execs the Envoy binary at envoyPath with "--mode validate" and the
+// args passed, writing its console output to out.
+//
+// This returns an error when Envoy rejects the configuration or can't start.
+func Validate(ctx context.Context, envoyPath string, args []string, out io.Writer) error {
+	args = append([]string{"--mode", "validate"}, args...)
+	cmd := exec.CommandContext(ctx, envoyPath, args...) // #nosec -> users can run whatever binary they like!
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("envoy exited with status %d", exitErr.ExitCode())
+		}
+		return fmt.Errorf("unable to start Envoy process: %w", err)
+	}
+	return nil
+}
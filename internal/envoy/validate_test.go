@@ -0,0 +1,51 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package envoy
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		expectedOut string
+		expectedErr string
+	}{
+		{
+			name:        "valid",
+			args:        []string{"--config-yaml", "static_resources: {}"},
+			expectedOut: "initializing epoch 0\nconfiguration '' OK\n",
+		},
+		{
+			name:        "invalid",
+			args:        []string{},
+			expectedOut: "initializing epoch 0\nexiting\nAt least one of --config-path or --config-yaml or Options::configProto() should be non-empty\n",
+			expectedErr: "envoy exited with status 1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := new(bytes.Buffer)
+			err := Validate(t.Context(), fakeEnvoyBin, tc.args, out)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedOut, out.String())
+		})
+	}
+}
+
+func TestValidate_MissingBinary(t *testing.T) {
+	err := Validate(t.Context(), filepath.Join(t.TempDir(), "envoy"), nil, new(bytes.Buffer))
+	require.ErrorContains(t, err, "unable to start Envoy process")
+}
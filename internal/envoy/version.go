@@ -12,7 +12,8 @@ import (
 )
 
 const (
-	currentVersionVar = "$ENVOY_VERSION"
+	// CurrentVersionVar is the source of the current version when set by the ENVOY_VERSION environment variable.
+	CurrentVersionVar = "$ENVOY_VERSION"
 	// CurrentVersionWorkingDirFile is used for stable "versions" and "help" output
 	CurrentVersionWorkingDirFile = "$PWD/.envoy-version"
 	// CurrentVersionConfigFile is used for stable "versions" and "help" output
@@ -54,7 +55,7 @@ func getCurrentVersion(versionFilePath, versionFileSource string) (v, source str
 	// Priority 1: $ENVOY_VERSION
 	if ev, ok := os.LookupEnv("ENVOY_VERSION"); ok {
 		v = ev
-		source = currentVersionVar
+		source = CurrentVersionVar
 		return v, source, err
 	}
 
@@ -87,5 +88,5 @@ func getDataVersion(versionFilePath string) (v string, err error) {
 // VersionUsageList is the priority order of Envoy version sources.
 // This includes unresolved variables as it is both used statically for markdown generation, and also at runtime.
 func VersionUsageList() string {
-	return strings.Join([]string{currentVersionVar, CurrentVersionWorkingDirFile, CurrentVersionConfigFile}, ", ")
+	return strings.Join([]string{CurrentVersionVar, CurrentVersionWorkingDirFile, CurrentVersionConfigFile}, ", ")
 }
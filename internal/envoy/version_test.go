@@ -96,7 +96,7 @@ func TestCurrentVersion(t *testing.T) {
 	t.Run("prefers $ENVOY_VERSION over $PWD/.envoy-version", func(t *testing.T) {
 		v, source, err := CurrentVersion(homeDir, versionFile, CurrentVersionConfigFile)
 		require.Equal(t, version.PatchVersion("3.3.3"), v)
-		require.Equal(t, currentVersionVar, source)
+		require.Equal(t, CurrentVersionVar, source)
 		require.NoError(t, err)
 	})
 }
@@ -9,33 +9,210 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 
 	"github.com/tetratelabs/func-e/internal/version"
 )
 
-// NewGetVersions creates a new Envoy versions fetcher.
-// TODO: validate the data before returning it!
+// NewGetVersions creates a new Envoy versions fetcher. It returns an error listing every problem in an invalid Envoy
+// versions JSON, instead of failing later, such as on install.
 func NewGetVersions(client *http.Client, envoyVersionsURL, ua string) version.GetReleaseVersions {
+	return NewCachedGetVersions(client, envoyVersionsURL, ua, "", false, nil)
+}
+
+// NewCachedGetVersions is like NewGetVersions, except it keeps the last Envoy versions JSON read in cacheFile, and
+// refreshes it with a conditional GET. When offline, only cacheFile is read, unless envoyVersionsURL is a "file://"
+// URL, as that doesn't need the network.
+//
+// When sv isn't nil, the Envoy versions JSON must match its signature, which is cached along with it.
+func NewCachedGetVersions(client *http.Client, envoyVersionsURL, ua, cacheFile string, offline bool, sv *SignatureVerifier) version.GetReleaseVersions {
 	return func(ctx context.Context) (*version.ReleaseVersions, error) {
-		// #nosec => This is by design, users can call out to wherever they like!
-		resp, err := httpGet(ctx, client, envoyVersionsURL, ua)
-		if err != nil {
-			return nil, err
+		var cached *versionsCache
+		if cacheFile != "" {
+			cached = readVersionsCache(cacheFile, envoyVersionsURL)
 		}
-		defer resp.Body.Close() //nolint:errcheck // body fully read below
 
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("received %v status code from %v", resp.StatusCode, envoyVersionsURL)
+		c := cached
+		if offline && !isFileURL(envoyVersionsURL) {
+			if cached == nil {
+				return nil, fmt.Errorf("offline, but there is no cached copy of %s: run func-e without --offline once to cache it", envoyVersionsURL)
+			}
+		} else {
+			var err error
+			if c, err = getVersions(ctx, client, envoyVersionsURL, ua, cached); err != nil {
+				return nil, err
+			}
+			if sv != nil && c.Signature == nil { // a new body, or one cached before signatures were verified
+				sig, err := getSignature(ctx, client, envoyVersionsURL, ua)
+				if err != nil {
+					return nil, err
+				}
+				if sig != nil && c == cached { // copy, so that the cache is updated with the signature
+					updated := *cached
+					c = &updated
+				}
+				c.Signature = sig
+			}
 		}
-		body, err := io.ReadAll(resp.Body) // fully read the response
-		if err != nil {
-			return nil, fmt.Errorf("error reading %s: %w", envoyVersionsURL, err)
+		if sv != nil {
+			if err := sv.verify(envoyVersionsURL, c.Body, c.Signature); err != nil {
+				return nil, err
+			}
 		}
 
 		result := version.ReleaseVersions{}
-		if err := json.Unmarshal(body, &result); err != nil {
+		if err := json.Unmarshal(c.Body, &result); err != nil {
 			return nil, fmt.Errorf("error unmarshalling Envoy versions: %w", err)
 		}
+		if err := result.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid Envoy versions JSON %s:\n%w", envoyVersionsURL, err)
+		}
+		if cacheFile != "" && c != cached {
+			// Failing to cache isn't a reason to fail, as the next invocation can try again.
+			_ = writeVersionsCache(cacheFile, c)
+		}
+		if err := resolveTarballURLs(&result, envoyVersionsURL); err != nil {
+			return nil, err
+		}
 		return &result, nil
 	}
 }
+
+// versionsCache is the format of the cacheFile of NewCachedGetVersions.
+type versionsCache struct {
+	// URL is where Body was read from, as the cache isn't valid for a different Envoy versions URL.
+	URL string `json:"url"`
+	// ETag and LastModified are the response headers of URL, used in a conditional GET.
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+	// Signature is the detached signature of Body, or nil if unsigned or not verified.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// getVersions GETs the Envoy versions JSON, or returns cached when it is unmodified.
+func getVersions(ctx context.Context, client *http.Client, envoyVersionsURL, ua string, cached *versionsCache) (*versionsCache, error) {
+	var header http.Header
+	if cached != nil {
+		header = http.Header{}
+		if cached.ETag != "" {
+			header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	// #nosec => This is by design, users can call out to wherever they like!
+	resp, err := httpGetWithHeader(ctx, client, envoyVersionsURL, ua, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // body fully read below
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("received %v status code from %v", resp.StatusCode, envoyVersionsURL)
+	}
+	body, err := io.ReadAll(resp.Body) // fully read the response
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", envoyVersionsURL, err)
+	}
+	return &versionsCache{
+		URL:          envoyVersionsURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Body:         body,
+	}, nil
+}
+
+// getSignature GETs the detached signature of the Envoy versions JSON, or returns nil if there is none.
+func getSignature(ctx context.Context, client *http.Client, envoyVersionsURL, ua string) ([]byte, error) {
+	sigURL := envoyVersionsURL + signatureSuffix
+	resp, err := httpGet(ctx, client, sigURL, ua)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck // body fully read below
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("received %v status code from %v", resp.StatusCode, sigURL)
+	}
+	sig, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", sigURL, err)
+	}
+	return sig, nil
+}
+
+// readVersionsCache returns the cached Envoy versions JSON, or nil if there isn't one for envoyVersionsURL.
+func readVersionsCache(cacheFile, envoyVersionsURL string) *versionsCache {
+	b, err := os.ReadFile(cacheFile) //nolint:gosec // cacheFile is in DataHome
+	if err != nil {
+		return nil
+	}
+	var c versionsCache
+	if err = json.Unmarshal(b, &c); err != nil || c.URL != envoyVersionsURL || len(c.Body) == 0 {
+		return nil
+	}
+	return &c
+}
+
+// writeVersionsCache writes to a temporary file, then renames it, so that concurrent func-e never read a partial one.
+func writeVersionsCache(cacheFile string, c *versionsCache) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(cacheFile), "."+filepath.Base(cacheFile)+"-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), cacheFile)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+	return err
+}
+
+// resolveTarballURLs resolves relative tarball URLs against the URL of the Envoy versions JSON. For example,
+// "1.31.2/envoy.tar.xz" in "file:///mnt/envoy/envoy-versions.json" is "file:///mnt/envoy/1.31.2/envoy.tar.xz".
+func resolveTarballURLs(evs *version.ReleaseVersions, envoyVersionsURL string) error {
+	base, err := url.Parse(envoyVersionsURL)
+	if err != nil {
+		return err
+	}
+	resolve := func(tarballs map[version.Platform]version.TarballURL) error {
+		for p, t := range tarballs {
+			ref, err := url.Parse(string(t))
+			if err != nil {
+				return fmt.Errorf("invalid tarball URL %q: %w", t, err)
+			}
+			tarballs[p] = version.TarballURL(base.ResolveReference(ref).String())
+		}
+		return nil
+	}
+	for _, r := range evs.Versions {
+		if err = resolve(r.Tarballs); err != nil {
+			return err
+		}
+	}
+	if evs.Dev != nil {
+		return resolve(evs.Dev.Tarballs)
+	}
+	return nil
+}
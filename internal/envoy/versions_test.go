@@ -4,6 +4,10 @@
 package envoy
 
 import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,3 +28,189 @@ func TestNewGetVersions(t *testing.T) {
 	require.NoError(t, err)
 	require.Contains(t, evs.Versions, version.LastKnownEnvoy)
 }
+
+func TestNewGetVersions_File(t *testing.T) {
+	dir := t.TempDir()
+	versionsJSON := `{
+  "versions": {
+    "1.31.2": {
+      "releaseDate": "2024-09-19",
+      "tarballs": {
+        "linux/amd64": "1.31.2/envoy-v1.31.2-linux-amd64.tar.xz",
+        "darwin/arm64": "https://example.com/envoy-v1.31.2-darwin-arm64.tar.xz"
+      }
+    }
+  },
+  "sha256sums": {
+    "envoy-v1.31.2-linux-amd64.tar.xz": "` + strings.Repeat("a", 64) + `",
+    "envoy-v1.31.2-darwin-arm64.tar.xz": "` + strings.Repeat("b", 64) + `"
+  }
+}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "envoy-versions.json"), []byte(versionsJSON), 0o600))
+	gv := NewGetVersions(http.DefaultClient, "file://"+dir+"/envoy-versions.json", globals.DefaultDevUserAgent)
+
+	evs, err := gv(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, map[version.Platform]version.TarballURL{
+		"linux/amd64":  version.TarballURL("file://" + dir + "/1.31.2/envoy-v1.31.2-linux-amd64.tar.xz"),
+		"darwin/arm64": "https://example.com/envoy-v1.31.2-darwin-arm64.tar.xz",
+	}, evs.Versions["1.31.2"].Tarballs)
+}
+
+func TestNewGetVersions_Invalid(t *testing.T) {
+	versionsURL := "http://" + admin.ServerAddr + "/envoy-versions.json"
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"versions":{"1.31":{"releaseDate":"2024-09-19"}},"sha256sums":{}}`))
+	})
+	gv := NewGetVersions(httptest.HTTPClient(handler), versionsURL, globals.DefaultDevUserAgent)
+
+	_, err := gv(t.Context())
+	require.EqualError(t, err, "invalid Envoy versions JSON "+versionsURL+":\n"+
+		`versions["1.31"]: invalid version: should look like "`+version.LastKnownEnvoy.String()+`"`)
+}
+
+func TestNewGetVersions_FileNotFound(t *testing.T) {
+	versionsURL := "file://" + t.TempDir() + "/envoy-versions.json"
+	gv := NewGetVersions(http.DefaultClient, versionsURL, globals.DefaultDevUserAgent)
+
+	_, err := gv(t.Context())
+	require.EqualError(t, err, "received 404 status code from "+versionsURL)
+}
+
+func TestNewCachedGetVersions(t *testing.T) {
+	baseURL := "http://" + admin.ServerAddr
+	versionsURL := baseURL + "/envoy-versions.json"
+	versionsHandler := test.NewEnvoyVersionsHandler(t, baseURL, version.LastKnownEnvoy)
+	var requests []*http.Request
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		versionsHandler.ServeHTTP(w, r)
+	})
+	cacheFile := filepath.Join(t.TempDir(), "envoy-versions-cache.json")
+	client := httptest.HTTPClient(handler)
+
+	t.Run("offline without a cache", func(t *testing.T) {
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, true, nil)
+
+		_, err := gv(t.Context())
+		require.EqualError(t, err, "offline, but there is no cached copy of "+versionsURL+": run func-e without --offline once to cache it")
+		require.Empty(t, requests)
+	})
+
+	t.Run("caches", func(t *testing.T) {
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, false, nil)
+
+		evs, err := gv(t.Context())
+		require.NoError(t, err)
+		require.Contains(t, evs.Versions, version.LastKnownEnvoy)
+		require.Len(t, requests, 1)
+		require.FileExists(t, cacheFile)
+	})
+
+	t.Run("conditional GET", func(t *testing.T) {
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, false, nil)
+
+		evs, err := gv(t.Context())
+		require.NoError(t, err)
+		require.Contains(t, evs.Versions, version.LastKnownEnvoy)
+		require.Len(t, requests, 2)
+		require.Equal(t, `"v1"`, requests[1].Header.Get("If-None-Match"))
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, true, nil)
+
+		evs, err := gv(t.Context())
+		require.NoError(t, err)
+		require.Contains(t, evs.Versions, version.LastKnownEnvoy)
+		require.Len(t, requests, 2)
+	})
+
+	t.Run("ignores the cache of another URL", func(t *testing.T) {
+		otherURL := versionsURL + "?other"
+		gv := NewCachedGetVersions(client, otherURL, globals.DefaultDevUserAgent, cacheFile, true, nil)
+
+		_, err := gv(t.Context())
+		require.EqualError(t, err, "offline, but there is no cached copy of "+otherURL+": run func-e without --offline once to cache it")
+
+		gv = NewCachedGetVersions(client, otherURL, globals.DefaultDevUserAgent, cacheFile, false, nil)
+		_, err = gv(t.Context())
+		require.NoError(t, err)
+		require.Len(t, requests, 3)
+		require.Empty(t, requests[2].Header.Get("If-None-Match"))
+	})
+}
+
+func TestNewCachedGetVersions_Signature(t *testing.T) {
+	versionsURL := "http://" + admin.ServerAddr + "/envoy-versions.json"
+	body := []byte(`{"versions":{"1.99.0":{"releaseDate":"2026-01-02"}}}`)
+	signer := newTestSigner(t, 1)
+	sig := signer.sign(body, "timestamp:1700000000")
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/envoy-versions.json", "/unsigned.json":
+			_, _ = w.Write(body)
+		case "/envoy-versions.json.sig":
+			_, _ = w.Write(sig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client := httptest.HTTPClient(handler)
+	sv := &SignatureVerifier{Keys: []PublicKey{{ID: signer.id, key: signer.pub}}, Required: true}
+
+	t.Run("untrusted", func(t *testing.T) {
+		other := newTestSigner(t, 2)
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, "", false,
+			&SignatureVerifier{Keys: []PublicKey{{ID: other.id, key: other.pub}}})
+
+		_, err := gv(t.Context())
+		require.EqualError(t, err, versionsURL+" is signed by key 0000000000000001, which isn't trusted")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		unsignedURL := "http://" + admin.ServerAddr + "/unsigned.json"
+		gv := NewCachedGetVersions(client, unsignedURL, globals.DefaultDevUserAgent, "", false, sv)
+
+		_, err := gv(t.Context())
+		require.EqualError(t, err, "signature required, but "+unsignedURL+" has no signature at "+unsignedURL+".sig")
+	})
+
+	cacheFile := filepath.Join(t.TempDir(), "envoy-versions-cache.json")
+	requests = nil
+
+	t.Run("verifies and caches the signature", func(t *testing.T) {
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, false, sv)
+
+		evs, err := gv(t.Context())
+		require.NoError(t, err)
+		require.Contains(t, evs.Versions, version.PatchVersion("1.99.0"))
+		require.Equal(t, []string{"/envoy-versions.json", "/envoy-versions.json.sig"}, requests)
+	})
+
+	t.Run("offline verifies the cached signature", func(t *testing.T) {
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, true, sv)
+
+		evs, err := gv(t.Context())
+		require.NoError(t, err)
+		require.Contains(t, evs.Versions, version.PatchVersion("1.99.0"))
+		require.Len(t, requests, 2)
+	})
+
+	t.Run("offline rejects a tampered cache", func(t *testing.T) {
+		b, err := os.ReadFile(cacheFile)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(cacheFile, []byte(strings.Replace(string(b), "1.99.0", "1.99.1", 1)), 0o600))
+		gv := NewCachedGetVersions(client, versionsURL, globals.DefaultDevUserAgent, cacheFile, true, sv)
+
+		_, err = gv(t.Context())
+		require.EqualError(t, err, versionsURL+" doesn't match its signature from key 0000000000000001")
+	})
+}
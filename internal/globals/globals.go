@@ -65,6 +65,17 @@ type GlobalOpts struct {
 	RuntimeDir string
 	// HomeDir is the FUNC_E_HOME directory. When set, legacy paths are used.
 	HomeDir string
+	// TarballCache means keep downloaded Envoy archives in EnvoyTarballsDir, so they can be reinstalled without the
+	// network.
+	TarballCache bool
+	// Offline means don't use the network. The Envoy versions JSON is read from EnvoyVersionsCacheFile, and only
+	// installed versions or archives in EnvoyTarballsDir are used.
+	Offline bool
+	// TrustedKeys are minisign public keys trusted to sign the Envoy versions JSON, in addition to those in
+	// TrustedKeysDir.
+	TrustedKeys []string
+	// RequireSignature means the Envoy versions JSON must have a valid signature from a trusted key.
+	RequireSignature bool
 	// Quiet means don't Logf to Out
 	Quiet bool
 	// Out is where status messages are written. Defaults to os.Stdout
@@ -77,6 +88,9 @@ type GlobalOpts struct {
 	UserAgent string
 	// GetEnvoyVersions returns Envoy release versions from EnvoyVersionsURL.
 	GetEnvoyVersions version.GetReleaseVersions
+	// GetCachedEnvoyVersions is like GetEnvoyVersions, except it never uses the network. It is used for warnings,
+	// such as when the Envoy version is out of support, so it is fine for it to fail.
+	GetCachedEnvoyVersions version.GetReleaseVersions
 }
 
 // Logf is used for shared functions that log conditionally on GlobalOpts.Quiet
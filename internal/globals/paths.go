@@ -20,6 +20,28 @@ func (o *GlobalOpts) EnvoyVersionsDir() string {
 	return filepath.Join(o.DataHome, "envoy-versions")
 }
 
+// EnvoyTarballsDir returns the directory containing downloaded Envoy archives.
+// There is no legacy location, as this was added after "$FUNC_E_HOME".
+// Default: "$dataHome/envoy-tarballs"
+func (o *GlobalOpts) EnvoyTarballsDir() string {
+	return filepath.Join(o.DataHome, "envoy-tarballs")
+}
+
+// EnvoyVersionsCacheFile returns the path to the last Envoy versions JSON read from EnvoyVersionsURL.
+// Legacy: "$homeDir/envoy-versions-cache.json"
+// Default: "$dataHome/envoy-versions-cache.json"
+func (o *GlobalOpts) EnvoyVersionsCacheFile() string {
+	return filepath.Join(o.DataHome, "envoy-versions-cache.json")
+}
+
+// TrustedKeysDir returns the directory containing minisign public keys ("*.pub") trusted to sign the Envoy
+// versions JSON.
+// Legacy: "$homeDir/trusted-keys"
+// Default: "$configHome/trusted-keys"
+func (o *GlobalOpts) TrustedKeysDir() string {
+	return filepath.Join(o.ConfigHome, "trusted-keys")
+}
+
 // EnvoyVersionFile returns the path to the selected version file.
 // Legacy: "$homeDir/version"
 // Default: "$configHome/envoy-version"
@@ -41,14 +63,21 @@ func (o *GlobalOpts) EnvoyVersionFileSource() string {
 	return "$FUNC_E_CONFIG_HOME/envoy-version"
 }
 
+// EnvoyRunsDir returns the directory containing all run directories.
+// Legacy: "$stateHome/runs"
+// Default: "$stateHome/envoy-runs"
+func (o *GlobalOpts) EnvoyRunsDir() string {
+	if o.HomeDir != "" {
+		return filepath.Join(o.StateHome, "runs")
+	}
+	return filepath.Join(o.StateHome, "envoy-runs")
+}
+
 // EnvoyRunDir returns the directory for a specific run (logs, config_dump.json, etc.).
 // Legacy: "$stateHome/runs/{runID}"
 // Default: "$stateHome/envoy-runs/{runID}"
 func (o *GlobalOpts) EnvoyRunDir(runID string) string {
-	if o.HomeDir != "" {
-		return filepath.Join(o.StateHome, "runs", runID)
-	}
-	return filepath.Join(o.StateHome, "envoy-runs", runID)
+	return filepath.Join(o.EnvoyRunsDir(), runID)
 }
 
 // EnvoyRuntimeDir returns the directory for temporary files of a specific run.
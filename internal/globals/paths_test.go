@@ -43,6 +43,72 @@ func TestEnvoyVersionsDir(t *testing.T) {
 	}
 }
 
+func TestEnvoyTarballsDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataHome string
+		homeDir  string
+		expected string
+	}{
+		{
+			name:     "separate directories",
+			dataHome: "/home/user/.local/share/func-e",
+			homeDir:  "",
+			expected: "/home/user/.local/share/func-e/envoy-tarballs",
+		},
+		{
+			name:     "legacy mode",
+			dataHome: "/home/user/func-e",
+			homeDir:  "/home/user/func-e",
+			expected: "/home/user/func-e/envoy-tarballs",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &GlobalOpts{
+				DataHome: tc.dataHome,
+				HomeDir:  tc.homeDir,
+			}
+			actual := o.EnvoyTarballsDir()
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestEnvoyVersionsCacheFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataHome string
+		homeDir  string
+		expected string
+	}{
+		{
+			name:     "separate directories",
+			dataHome: "/home/user/.local/share/func-e",
+			homeDir:  "",
+			expected: "/home/user/.local/share/func-e/envoy-versions-cache.json",
+		},
+		{
+			name:     "legacy mode",
+			dataHome: "/home/user/func-e",
+			homeDir:  "/home/user/func-e",
+			expected: "/home/user/func-e/envoy-versions-cache.json",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &GlobalOpts{
+				DataHome: tc.dataHome,
+				HomeDir:  tc.homeDir,
+			}
+			actual := o.EnvoyVersionsCacheFile()
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestEnvoyVersionFile(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -80,6 +146,72 @@ func TestEnvoyVersionFile(t *testing.T) {
 	}
 }
 
+func TestTrustedKeysDir(t *testing.T) {
+	tests := []struct {
+		name       string
+		configHome string
+		homeDir    string
+		expected   string
+	}{
+		{
+			name:       "separate directories",
+			configHome: "/home/user/.config/func-e",
+			homeDir:    "",
+			expected:   "/home/user/.config/func-e/trusted-keys",
+		},
+		{
+			name:       "legacy mode",
+			configHome: "/home/user/func-e",
+			homeDir:    "/home/user/func-e",
+			expected:   "/home/user/func-e/trusted-keys",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &GlobalOpts{
+				ConfigHome: tc.configHome,
+				HomeDir:    tc.homeDir,
+			}
+			actual := o.TrustedKeysDir()
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestEnvoyRunsDir(t *testing.T) {
+	tests := []struct {
+		name      string
+		stateHome string
+		homeDir   string
+		expected  string
+	}{
+		{
+			name:      "separate directories",
+			stateHome: "/home/user/.local/state/func-e",
+			homeDir:   "",
+			expected:  "/home/user/.local/state/func-e/envoy-runs",
+		},
+		{
+			name:      "legacy mode",
+			stateHome: "/home/user/func-e",
+			homeDir:   "/home/user/func-e",
+			expected:  "/home/user/func-e/runs",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &GlobalOpts{
+				StateHome: tc.stateHome,
+				HomeDir:   tc.homeDir,
+			}
+			actual := o.EnvoyRunsDir()
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestEnvoyRunDir(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -50,12 +50,14 @@ func initOpts(ctx context.Context, options ...api.RunOption) (*globals.GlobalOpt
 	}
 
 	o := &globals.GlobalOpts{
-		EnvoyVersion: version.PatchVersion(ro.EnvoyVersion),
-		Out:          ro.Out,
-		ConfigHome:   ro.ConfigHome,
-		DataHome:     ro.DataHome,
-		StateHome:    ro.StateHome,
-		RuntimeDir:   ro.RuntimeDir,
+		EnvoyVersion:     version.PatchVersion(ro.EnvoyVersion),
+		Out:              ro.Out,
+		ConfigHome:       ro.ConfigHome,
+		DataHome:         ro.DataHome,
+		StateHome:        ro.StateHome,
+		RuntimeDir:       ro.RuntimeDir,
+		TrustedKeys:      ro.TrustedKeys,
+		RequireSignature: ro.RequireSignature,
 		RunOpts: globals.RunOpts{
 			EnvoyPath:   ro.EnvoyPath,
 			EnvoyOut:    ro.EnvoyOut,
@@ -85,8 +85,21 @@ func InitializeGlobalOpts(o *globals.GlobalOpts, envoyVersionsURL, envoyPath, ho
 			o.UserAgent = fmt.Sprintf("func-e/%s (%s)", o.Version, o.Platform)
 		}
 	}
-	if o.GetEnvoyVersions == nil { // not overridden for tests
-		o.GetEnvoyVersions = envoy.NewGetVersions(o.HTTPClient, o.EnvoyVersionsURL, o.UserAgent)
+	if o.GetEnvoyVersions == nil || o.GetCachedEnvoyVersions == nil { // not overridden for tests
+		var keys []envoy.PublicKey
+		if keys, err = envoy.LoadTrustedKeys(o.TrustedKeysDir(), o.TrustedKeys); err != nil {
+			return err
+		}
+		var sv *envoy.SignatureVerifier
+		if len(keys) > 0 || o.RequireSignature {
+			sv = &envoy.SignatureVerifier{Keys: keys, Required: o.RequireSignature}
+		}
+		if o.GetEnvoyVersions == nil {
+			o.GetEnvoyVersions = envoy.NewCachedGetVersions(o.HTTPClient, o.EnvoyVersionsURL, o.UserAgent, o.EnvoyVersionsCacheFile(), o.Offline, sv)
+		}
+		if o.GetCachedEnvoyVersions == nil {
+			o.GetCachedEnvoyVersions = envoy.NewCachedGetVersions(o.HTTPClient, o.EnvoyVersionsURL, o.UserAgent, o.EnvoyVersionsCacheFile(), true, sv)
+		}
 	}
 
 	// Create base XDG directories now that all paths are configured
@@ -165,7 +178,7 @@ func getEnvoyVersionsURL(versionsURL string) (string, error) {
 		return globals.DefaultEnvoyVersionsURL, nil
 	}
 	otherURL, err := url.Parse(versionsURL)
-	if err != nil || otherURL.Host == "" || otherURL.Scheme == "" {
+	if err != nil || otherURL.Scheme == "" || (otherURL.Host == "" && (otherURL.Scheme != "file" || otherURL.Path == "")) {
 		return "", fmt.Errorf("%q is not a valid Envoy versions URL", versionsURL)
 	}
 	return versionsURL, nil
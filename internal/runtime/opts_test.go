@@ -65,6 +65,16 @@ func TestInitializeGlobalOpts(t *testing.T) {
 			envoyVersionsURL: "http://versions/arg",
 			expected:         globals.GlobalOpts{ConfigHome: defaultConfigHome, DataHome: defaultDataHome, StateHome: defaultStateHome, RuntimeDir: defaultRuntimeDir, Platform: defaultPlatform, EnvoyVersionsURL: "http://versions/arg"},
 		},
+		{
+			name:             "--envoy-versions-url file URL",
+			envoyVersionsURL: "file:///mnt/envoy/envoy-versions.json",
+			expected:         globals.GlobalOpts{ConfigHome: defaultConfigHome, DataHome: defaultDataHome, StateHome: defaultStateHome, RuntimeDir: defaultRuntimeDir, Platform: defaultPlatform, EnvoyVersionsURL: "file:///mnt/envoy/envoy-versions.json"},
+		},
+		{
+			name:             "--envoy-versions-url file URL without a path",
+			envoyVersionsURL: "file://",
+			expectedErr:      `"file://" is not a valid Envoy versions URL`,
+		},
 		{
 			name:      "--envoy-path flag",
 			envoyPath: "/opt/envoy/bin/envoy",
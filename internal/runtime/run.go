@@ -9,6 +9,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/tetratelabs/func-e/internal/envoy"
 	"github.com/tetratelabs/func-e/internal/globals"
@@ -16,38 +17,83 @@ import (
 )
 
 // EnsurePatchVersion ensures we either have a valid version.PatchVersion or an error
+// A version.MinorVersion resolves to its latest patch, a version.Constraint to the latest version meeting it, and a
+// version.Channel to the version it selects.
 // If remote lookup of the latest patch fails, this logs and falls back to the last installed one
+// When offline, the last installed one is used first, and the remote lookup only reads the cached versions.
 // NOTE: Warnings and errors include the platform because a release isn't available at the same time for all platforms.
 func EnsurePatchVersion(ctx context.Context, o *globals.GlobalOpts, v version.Version) (version.PatchVersion, error) {
-	if mv, ok := v.(version.MinorVersion); ok {
-		o.Logf("looking up the latest patch for Envoy version %s\n", mv)
-		evs, err := o.GetEnvoyVersions(ctx)
-		var patchVersions []version.PatchVersion
-		if err == nil {
-			patchVersions = versionsForPlatform(evs.Versions, o.Platform)
-			if pv := version.FindLatestPatchVersion(patchVersions, mv); pv != "" {
-				return pv, nil
-			}
-			err = fmt.Errorf("%s does not contain an Envoy release for version %s on platform %s", o.EnvoyVersionsURL, mv, o.Platform)
+	var findLatest func([]version.PatchVersion) version.PatchVersion
+	lookingUp := "the latest patch for Envoy version " + v.String()
+	switch vv := v.(type) {
+	case version.PatchVersion:
+		return vv, nil
+	case version.MinorVersion:
+		findLatest = func(pvs []version.PatchVersion) version.PatchVersion {
+			return version.FindLatestPatchVersion(pvs, vv)
+		}
+	case version.Constraint:
+		findLatest = func(pvs []version.PatchVersion) version.PatchVersion {
+			return version.FindLatestMatchingVersion(pvs, vv)
 		}
+		lookingUp = "the latest Envoy version matching " + v.String()
+	case version.Channel:
+		findLatest = func(pvs []version.PatchVersion) version.PatchVersion {
+			return version.FindChannelVersion(pvs, vv)
+		}
+		lookingUp = "the " + v.String() + " Envoy version"
+	default: // version.Version is a union type, so this is impossible
+		panic(fmt.Sprintf("unexpected version type %T", v))
+	}
 
-		// Attempt the last installed version instead of raising an error. There may not be one!
+	if o.Offline { // Installing another patch needs the network or the tarball cache, so prefer an installed one.
 		if rows, e := getInstalledVersions(o.EnvoyVersionsDir()); e == nil {
+			var installed []version.PatchVersion
 			for _, r := range rows {
-				patchVersions = append(patchVersions, r.version)
+				installed = append(installed, r.version)
 			}
-			if pv := version.FindLatestPatchVersion(patchVersions, mv); pv != "" {
-				o.Logf("couldn't look up an Envoy release for version %s on platform %s: using last installed version\n", mv, o.Platform)
+			if pv := findLatest(installed); pv != "" {
 				return pv, nil
 			}
 		}
-		return "", err
-	} // version.Version is a union type, so the only other option is a patch!
-	vv, ok := v.(version.PatchVersion)
-	if !ok {
-		panic(fmt.Sprintf("unexpected version type %T", v))
 	}
-	return vv, nil
+	o.Logf("looking up %s\n", lookingUp)
+	evs, err := o.GetEnvoyVersions(ctx)
+	var patchVersions []version.PatchVersion
+	if err == nil {
+		patchVersions = versionsForPlatform(evs.Versions, o.Platform)
+		if pv := findLatest(patchVersions); pv != "" {
+			return pv, nil
+		}
+		err = fmt.Errorf("%s does not contain an Envoy release for version %s on platform %s", o.EnvoyVersionsURL, v, o.Platform)
+	}
+
+	// Attempt the last installed version instead of raising an error. There may not be one!
+	if rows, e := getInstalledVersions(o.EnvoyVersionsDir()); e == nil {
+		for _, r := range rows {
+			patchVersions = append(patchVersions, r.version)
+		}
+		if pv := findLatest(patchVersions); pv != "" {
+			o.Logf("couldn't look up an Envoy release for version %s on platform %s: using last installed version\n", v, o.Platform)
+			return pv, nil
+		}
+	}
+	return "", err
+}
+
+// WarnIfUnsupported logs a warning if Envoy no longer supports the minor version of v. This only reads the cached
+// Envoy versions JSON, so that a pinned version doesn't need the network, and says nothing if it can't be read.
+func WarnIfUnsupported(ctx context.Context, o *globals.GlobalOpts, v version.PatchVersion) {
+	if o.GetCachedEnvoyVersions == nil {
+		return
+	}
+	evs, err := o.GetCachedEnvoyVersions(ctx)
+	if err != nil {
+		return
+	}
+	if end, ok := evs.SupportEnd(v); ok && !evs.IsSupported(v, time.Now()) {
+		o.Logf("warning: Envoy %s is out of support since %s: consider \"func-e use %s\"\n", v, end, version.Stable)
+	}
 }
 
 // Run runs Envoy with the given arguments.
@@ -77,6 +123,21 @@ func Run(ctx context.Context, o *globals.GlobalOpts, args []string) error {
 	r.ErrFile = stderrLog
 	r.Err = io.MultiWriter(o.EnvoyErr, stderrLog)
 
+	// Record the run, so that it can be inspected later. Envoy's runtime updates this when it starts and exits.
+	info := &envoy.RunInfo{
+		RunID:        o.RunOpts.RunID,
+		StartTime:    time.Now(),
+		EnvoyVersion: o.EnvoyVersion.String(),
+		EnvoyPath:    o.EnvoyPath,
+		Args:         args,
+	}
+	if err = envoy.WriteRunInfo(stateDir, info); err != nil {
+		// Only log this, like updateRunInfo, as run metadata shouldn't interfere with running Envoy.
+		_, _ = fmt.Fprintf(r.Err, "failed to write run info: %s\n", err)
+	} else {
+		r.RunInfo = info
+	}
+
 	return r.Run(ctx, args)
 }
 
@@ -138,7 +199,7 @@ func initializeRunOpts(ctx context.Context, o *globals.GlobalOpts) error {
 func versionsForPlatform(vs map[version.PatchVersion]version.Release, p version.Platform) []version.PatchVersion {
 	var patchVersions []version.PatchVersion
 	for k, v := range vs {
-		if _, ok := v.Tarballs[p]; ok {
+		if v.HasPlatform(p) {
 			patchVersions = append(patchVersions, k)
 		}
 	}
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -250,6 +251,131 @@ func TestEnsurePatchVersion(t *testing.T) {
 	require.Contains(t, o.Out.(*bytes.Buffer).String(), "looking up the latest patch for Envoy version 1.18\n")
 }
 
+func TestEnsurePatchVersion_Constraint(t *testing.T) {
+	versions := map[version.PatchVersion]version.Release{
+		version.PatchVersion("1.30.9"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.31.2"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.31.3"):       {Tarballs: map[version.Platform]version.TarballURL{"solaris/sparc64": ""}},
+		version.PatchVersion("1.31.4_debug"): {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.32.0"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+	}
+
+	homeDir := t.TempDir()
+	o := &globals.GlobalOpts{
+		GetEnvoyVersions: func(context.Context) (*version.ReleaseVersions, error) {
+			return &version.ReleaseVersions{Versions: versions}, nil
+		},
+		EnvoyVersionsURL: "fake URL", // for logging
+		ConfigHome:       homeDir,
+		DataHome:         homeDir,
+		StateHome:        homeDir,
+		RuntimeDir:       homeDir,
+		Out:              new(bytes.Buffer), // we expect logging
+		Platform:         globals.DefaultPlatform,
+	}
+
+	actual, err := EnsurePatchVersion(t.Context(), o, version.Constraint(">=1.30 <1.32"))
+	require.NoError(t, err)
+	require.Equal(t, version.PatchVersion("1.31.2"), actual)
+	require.Equal(t, "looking up the latest Envoy version matching >=1.30 <1.32\n", o.Out.(*bytes.Buffer).String())
+
+	_, err = EnsurePatchVersion(t.Context(), o, version.Constraint("~1.33"))
+	require.EqualError(t, err, fmt.Sprintf("fake URL does not contain an Envoy release for version ~1.33 on platform %s", o.Platform))
+
+	// Like a minor version, this falls back to an installed version.
+	require.NoError(t, os.MkdirAll(filepath.Join(o.DataHome, "envoy-versions", "1.33.1"), 0o700))
+	actual, err = EnsurePatchVersion(t.Context(), o, version.Constraint("~1.33"))
+	require.NoError(t, err)
+	require.Equal(t, version.PatchVersion("1.33.1"), actual)
+	require.Contains(t, o.Out.(*bytes.Buffer).String(), "couldn't look up an Envoy release for version ~1.33 on platform "+string(o.Platform)+": using last installed version\n")
+}
+
+func TestEnsurePatchVersion_Channel(t *testing.T) {
+	versions := map[version.PatchVersion]version.Release{
+		version.PatchVersion("1.30.9"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.31.2"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.31.3"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.32.0"):       {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.32.1_debug"): {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+		version.PatchVersion("1.33.0"):       {Tarballs: map[version.Platform]version.TarballURL{"solaris/sparc64": ""}},
+	}
+
+	tests := []struct {
+		channel         version.Channel
+		expected        version.PatchVersion
+		expectedLogging string
+	}{
+		{channel: version.Latest, expected: "1.32.0", expectedLogging: "looking up the latest Envoy version\n"},
+		{channel: version.Stable, expected: "1.31.3", expectedLogging: "looking up the stable Envoy version\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.channel.String(), func(t *testing.T) {
+			homeDir := t.TempDir()
+			o := &globals.GlobalOpts{
+				GetEnvoyVersions: func(context.Context) (*version.ReleaseVersions, error) {
+					return &version.ReleaseVersions{Versions: versions}, nil
+				},
+				DataHome: homeDir,
+				Out:      new(bytes.Buffer), // we expect logging
+				Platform: globals.DefaultPlatform,
+			}
+
+			actual, err := EnsurePatchVersion(t.Context(), o, tc.channel)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.expectedLogging, o.Out.(*bytes.Buffer).String())
+		})
+	}
+}
+
+func TestWarnIfUnsupported(t *testing.T) {
+	now := time.Now()
+	old := version.ReleaseDate(now.AddDate(-2, 0, 0).Format("2006-01-02"))
+	recent := version.ReleaseDate(now.AddDate(0, -1, 0).Format("2006-01-02"))
+	versions := &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+		"1.30.0": {ReleaseDate: old},
+		"1.31.0": {ReleaseDate: recent},
+	}}
+	end := version.ReleaseDate(now.AddDate(-1, 0, 0).Format("2006-01-02"))
+
+	tests := []struct {
+		name            string
+		version         version.PatchVersion
+		getCached       version.GetReleaseVersions
+		expectedLogging string
+	}{
+		{
+			name:            "out of support",
+			version:         "1.30.0",
+			expectedLogging: fmt.Sprintf("warning: Envoy 1.30.0 is out of support since %s: consider \"func-e use stable\"\n", end),
+		},
+		{name: "supported", version: "1.31.0"},
+		{name: "unknown", version: "1.29.0"},
+		{
+			name:    "not cached",
+			version: "1.30.0",
+			getCached: func(context.Context) (*version.ReleaseVersions, error) {
+				return nil, errors.New("offline, but there is no cached copy")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &globals.GlobalOpts{Out: new(bytes.Buffer), GetCachedEnvoyVersions: tc.getCached}
+			if o.GetCachedEnvoyVersions == nil {
+				o.GetCachedEnvoyVersions = func(context.Context) (*version.ReleaseVersions, error) {
+					return versions, nil
+				}
+			}
+
+			WarnIfUnsupported(t.Context(), o, tc.version)
+			require.Equal(t, tc.expectedLogging, o.Out.(*bytes.Buffer).String())
+		})
+	}
+}
+
 func TestEnsurePatchVersion_NotFound(t *testing.T) {
 	versions := map[version.PatchVersion]version.Release{
 		version.PatchVersion("1.18.14"):   {Tarballs: map[version.Platform]version.TarballURL{"solaris/sparc64": ""}},
@@ -366,6 +492,37 @@ func TestEnsurePatchVersion_FallbackFailure(t *testing.T) {
 	require.Contains(t, o.Out.(*bytes.Buffer).String(), "looking up the latest patch for Envoy version 1.18\n")
 }
 
+func TestEnsurePatchVersion_OfflinePrefersInstalled(t *testing.T) {
+	homeDir := t.TempDir()
+	o := &globals.GlobalOpts{
+		GetEnvoyVersions: func(context.Context) (*version.ReleaseVersions, error) {
+			return &version.ReleaseVersions{Versions: map[version.PatchVersion]version.Release{
+				"1.18.14": {Tarballs: map[version.Platform]version.TarballURL{globals.DefaultPlatform: ""}},
+			}}, nil
+		},
+		EnvoyVersionsURL: "fake URL", // for logging
+		ConfigHome:       homeDir,
+		DataHome:         homeDir,
+		StateHome:        homeDir,
+		RuntimeDir:       homeDir,
+		Out:              new(bytes.Buffer),
+		Platform:         globals.DefaultPlatform,
+		Offline:          true,
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(o.DataHome, "envoy-versions", "1.18.3"), 0o700))
+
+	// The cached versions have a newer patch, but it isn't installed.
+	actual, err := EnsurePatchVersion(t.Context(), o, version.MinorVersion("1.18"))
+	require.NoError(t, err)
+	require.Equal(t, version.PatchVersion("1.18.3"), actual)
+	require.Empty(t, o.Out.(*bytes.Buffer).String())
+
+	// Without an installed patch, the cached versions are used.
+	actual, err = EnsurePatchVersion(t.Context(), o, version.MinorVersion("1.19"))
+	require.EqualError(t, err, fmt.Sprintf("fake URL does not contain an Envoy release for version 1.19 on platform %s", o.Platform))
+	require.Empty(t, actual)
+}
+
 func TestVersionsForPlatform(t *testing.T) {
 	type testCase struct {
 		name     string
@@ -19,6 +19,7 @@ import (
 	pathpkg "path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ulikunitz/xz/v2"
 
@@ -147,7 +148,8 @@ func extractFile(dst *os.Root, name string, src io.Reader, perm os.FileMode) (er
 // TarGz tars and gzips "src", rooted at the last directory, into the file named "dst"
 // Ex If "src" includes "/tmp/envoy/bin" and "/tmp/build/bin". If "src" is "/tmp/envoy", "dst" includes "envoy/bin".
 //
-// This is used to compress the working directory of Envoy after it is stopped.
+// The archive is reproducible: the same files have the same SHA-256 sum, regardless of their modification times,
+// owners or umask. This is used to serve installed Envoy versions from "func-e mirror serve".
 func TarGz(dst, src string) error {
 	srcFS := os.DirFS(filepath.Dir(src))
 	basePath := filepath.Base(src)
@@ -186,6 +188,7 @@ func TarGz(dst, src string) error {
 
 		// Ensure the destination file starts at the intended path
 		header.Name = path
+		reproducible(header)
 		if err := tw.WriteHeader(header); err != nil {
 			return err
 		}
@@ -200,6 +203,19 @@ func TarGz(dst, src string) error {
 	})
 }
 
+// reproducible clears the fields of header that vary between hosts, and normalizes permissions to 0o755 or 0o644.
+func reproducible(header *tar.Header) {
+	header.ModTime = time.Unix(0, 0)
+	header.AccessTime, header.ChangeTime = time.Time{}, time.Time{}
+	header.Uid, header.Gid = 0, 0
+	header.Uname, header.Gname = "", ""
+	if header.Typeflag == tar.TypeDir || header.Mode&0o111 != 0 {
+		header.Mode = 0o755
+	} else {
+		header.Mode = 0o644
+	}
+}
+
 // Copy the contents of the file into the tar without buffering
 func cp(dst io.Writer, src fs.FS, path string, n int64) error { // dst, src order like io.Copy
 	f, err := src.Open(path)
@@ -8,11 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -214,3 +216,34 @@ func TestTarGZ(t *testing.T) {
 
 	requireTestFiles(t, tempDir)
 }
+
+func TestTarGZ_Reproducible(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "foo")
+	require.NoError(t, os.CopyFS(src, os.DirFS(filepath.Join("testdata", "foo"))))
+	first := filepath.Join(t.TempDir(), "first.tar.gz")
+	require.NoError(t, TarGz(first, src))
+
+	// Neither modification times nor permissions limited by a umask change the archive.
+	mtime := time.Date(2021, 5, 11, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err = os.Chmod(path, info.Mode().Perm()&0o700); err != nil { // like umask 077
+			return err
+		}
+		return os.Chtimes(path, mtime, mtime)
+	}))
+	second := filepath.Join(t.TempDir(), "second.tar.gz")
+	require.NoError(t, TarGz(second, src))
+
+	firstBytes, err := os.ReadFile(first)
+	require.NoError(t, err)
+	secondBytes, err := os.ReadFile(second)
+	require.NoError(t, err)
+	require.Equal(t, firstBytes, secondBytes)
+}
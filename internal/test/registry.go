@@ -0,0 +1,165 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tetratelabs/func-e/internal/version"
+)
+
+// RegistryToken is the anonymous token FakeRegistry requires, from its "/token" endpoint.
+const RegistryToken = "fake-token"
+
+// FakeRegistry is an OCI distribution registry that serves one Envoy image as an index of platforms. Like Docker
+// Hub, it challenges requests without RegistryToken. Use it with httptest.HTTPClient, as it answers any host.
+type FakeRegistry struct {
+	// Repository is the name of the image, such as "envoyproxy/envoy".
+	Repository string
+	// Manifests maps a tag or digest to a manifest or index, which can be changed to test verification.
+	Manifests map[string][]byte
+	// Blobs maps a digest to a layer, which can be changed to test verification.
+	Blobs map[string][]byte
+	// Pulled are the digests of the blobs served, in order.
+	Pulled []string
+}
+
+// NewFakeRegistry returns a FakeRegistry with an index tagged tag, with a manifest for each platform in envoys. Each
+// manifest has a gzipped layer with a stale Envoy and config, then an uncompressed layer with envoys[platform].
+func NewFakeRegistry(t *testing.T, repository, tag string, envoys map[version.Platform][]byte) *FakeRegistry {
+	t.Helper()
+	r := &FakeRegistry{Repository: repository, Manifests: map[string][]byte{}, Blobs: map[string][]byte{}}
+
+	var manifests []map[string]any
+	for _, p := range slices.Sorted(maps.Keys(envoys)) {
+		base := r.addBlob(requireLayer(t, true, map[string]string{
+			"usr/local/bin/envoy":  "stale",
+			"etc/envoy/envoy.yaml": "admin: {}",
+		}))
+		top := r.addBlob(requireLayer(t, false, map[string]string{"usr/local/bin/envoy": string(envoys[p])}))
+		manifest := r.addManifest(t, "", map[string]any{
+			"schemaVersion": 2,
+			"mediaType":     "application/vnd.oci.image.manifest.v1+json",
+			"layers": []map[string]any{
+				{"mediaType": "application/vnd.oci.image.layer.v1.tar+gzip", "digest": base, "size": len(r.Blobs[base])},
+				{"mediaType": "application/vnd.oci.image.layer.v1.tar", "digest": top, "size": len(r.Blobs[top])},
+			},
+		})
+		goos, goarch, _ := strings.Cut(string(p), "/")
+		manifests = append(manifests, map[string]any{
+			"mediaType": "application/vnd.oci.image.manifest.v1+json",
+			"digest":    manifest,
+			"size":      len(r.Manifests[manifest]),
+			"platform":  map[string]string{"os": goos, "architecture": goarch},
+		})
+	}
+	r.addManifest(t, tag, map[string]any{
+		"schemaVersion": 2,
+		"mediaType":     "application/vnd.oci.image.index.v1+json",
+		"manifests":     manifests,
+	})
+	return r
+}
+
+// Digest returns the digest of the manifest or index with the given tag.
+func (r *FakeRegistry) Digest(tag string) string {
+	return digest(r.Manifests[tag])
+}
+
+func (r *FakeRegistry) addBlob(b []byte) string {
+	d := digest(b)
+	r.Blobs[d] = b
+	return d
+}
+
+// addManifest adds the manifest m by digest, and by tag unless empty, returning its digest.
+func (r *FakeRegistry) addManifest(t *testing.T, tag string, m map[string]any) string {
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	d := digest(b)
+	r.Manifests[d] = b
+	if tag != "" {
+		r.Manifests[tag] = b
+	}
+	return d
+}
+
+func (r *FakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"token":%q}`, RegistryToken)
+		return
+	}
+	if req.Header.Get("Authorization") != "Bearer "+RegistryToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:%s:pull"`, r.Repository))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	prefix := "/v2/" + r.Repository + "/"
+	kind, ref, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, prefix), "/")
+	switch {
+	case !strings.HasPrefix(req.URL.Path, prefix):
+		http.NotFound(w, req)
+	case kind == "manifests" && r.Manifests[ref] != nil:
+		var m struct {
+			MediaType string `json:"mediaType"`
+		}
+		_ = json.Unmarshal(r.Manifests[ref], &m)
+		w.Header().Set("Content-Type", m.MediaType)
+		_, _ = w.Write(r.Manifests[ref])
+	case kind == "blobs" && r.Blobs[ref] != nil:
+		r.Pulled = append(r.Pulled, ref)
+		_, _ = w.Write(r.Blobs[ref])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// requireLayer returns a layer with the given files, and their parent directories, gzipped or not.
+func requireLayer(t *testing.T, gzipped bool, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	dirs := map[string]bool{}
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		for i, c := range name {
+			if dir := name[:i+1]; c == '/' && !dirs[dir] {
+				dirs[dir] = true
+				require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir, Mode: 0o755}))
+			}
+		}
+		content := files[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o755, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	if !gzipped {
+		return buf.Bytes()
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, err := zw.Write(buf.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return gz.Bytes()
+}
+
+func digest(b []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+}
@@ -11,7 +11,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
-	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -92,10 +91,10 @@ func (s *server) init(baseURL string, v version.PatchVersion) {
 	fakeEnvoyTarGz, sha256Sum := RequireFakeEnvoyTarGz(s.t, v)
 	s.fakeEnvoyTarGz = fakeEnvoyTarGz
 	for _, u := range s.versions.Versions[v].Tarballs {
-		s.versions.SHA256Sums[version.Tarball(path.Base(string(u)))] = sha256Sum
+		s.versions.SHA256Sums[u.Tarball()] = sha256Sum
 	}
 	for _, u := range s.versions.Dev.Tarballs {
-		s.versions.SHA256Sums[version.Tarball(path.Base(string(u)))] = sha256Sum
+		s.versions.SHA256Sums[u.Tarball()] = sha256Sum
 	}
 	versionsJSON, err := json.Marshal(s.versions)
 	require.NoError(s.t, err)
@@ -54,6 +54,12 @@ type listenerStatus struct {
 
 var listenerStatuses []listenerStatus
 
+// quitquitquit is closed when the admin endpoint of the same name is called.
+var (
+	quitquitquit = make(chan struct{})
+	quitOnce     sync.Once
+)
+
 // main simulates the behavior of real Envoy for testing purposes:
 // - Validates configuration arguments and requires at least one config source
 // - Sets up HTTP listeners based on static configurations
@@ -68,7 +74,7 @@ var listenerStatuses []listenerStatus
 // timeouts or other behaviors that don't match real Envoy.
 func main() {
 	// Parse and validate arguments
-	adminAddressPath, configPath, configYaml := parseArgs()
+	adminAddressPath, configPath, configYaml, mode := parseArgs()
 
 	// Initialize epoch
 	fprintf(os.Stderr, "initializing epoch 0\n")
@@ -83,6 +89,12 @@ func main() {
 		exit(1, err.Error())
 	}
 
+	if mode == "validate" {
+		// Envoy writes this to stdout, even when only --config-yaml is set.
+		fmt.Fprintf(os.Stdout, "configuration '%s' OK\n", configPath)
+		os.Exit(0)
+	}
+
 	// Trap signals for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -118,7 +130,7 @@ func fprintf(w io.Writer, format string, args ...interface{}) {
 }
 
 // parseArgs processes command-line arguments, collecting config-related flags and detecting admin path.
-func parseArgs() (adminAddressPath, configPath, configYaml string) {
+func parseArgs() (adminAddressPath, configPath, configYaml, mode string) {
 	for i := 1; i < len(os.Args); i++ {
 		arg := os.Args[i]
 		switch {
@@ -165,6 +177,13 @@ func parseArgs() (adminAddressPath, configPath, configYaml string) {
 				exit(1, "(--admin-address-path) -- Argument already set!")
 			}
 			adminAddressPath = strings.TrimPrefix(arg, "--admin-address-path=")
+		case arg == "--mode":
+			if i+1 < len(os.Args) {
+				i++
+				mode = os.Args[i]
+			}
+		case strings.HasPrefix(arg, "--mode="):
+			mode = strings.TrimPrefix(arg, "--mode=")
 		case arg == "-l" || arg == "--log-level":
 			if currentLogLevel != logLevelInfo {
 				exit(1, "-l (--log-level) -- Argument already set!")
@@ -180,7 +199,7 @@ func parseArgs() (adminAddressPath, configPath, configYaml string) {
 			currentLogLevel = parseLogLevel(strings.TrimPrefix(arg, "--log-level="))
 		}
 	}
-	return adminAddressPath, configPath, configYaml
+	return adminAddressPath, configPath, configYaml, mode
 }
 
 // parseLogLevel converts a log level string to logLevel enum
@@ -290,15 +309,19 @@ func startAdminServer(adminAddress, adminAddressPath string, wg *sync.WaitGroup,
 
 // handleShutdown waits for a signal and gracefully shuts down all servers and listeners.
 func handleShutdown(sigChan <-chan os.Signal, wg *sync.WaitGroup, servers []*http.Server, listeners []net.Listener) {
-	s := <-sigChan
 	var msg string
-	switch s {
-	case os.Interrupt:
-		msg = "caught SIGINT"
-	case syscall.SIGTERM:
-		msg = "caught ENVOY_SIGTERM"
-	default:
-		msg = "caught signal"
+	select {
+	case s := <-sigChan:
+		switch s {
+		case os.Interrupt:
+			msg = "caught SIGINT"
+		case syscall.SIGTERM:
+			msg = "caught ENVOY_SIGTERM"
+		default:
+			msg = "caught signal"
+		}
+	case <-quitquitquit:
+		msg = "shutting down server instance"
 	}
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
@@ -349,6 +372,29 @@ func adminEndpoints(w http.ResponseWriter, r *http.Request) {
 			ListenerStatuses []listenerStatus `json:"listener_statuses"`
 		}{ListenerStatuses: listenerStatuses})
 		w.Write(b)
+	case "/logging":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprintf(w, "Method %s not allowed, POST required.", r.Method)
+			return
+		}
+		level := r.URL.Query().Get("level")
+		if level != "" {
+			currentLogLevel = parseLogLevel(level)
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "active loggers:%s  main: %s%s", lf, strings.ToLower(level), lf)
+	case "/quitquitquit":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			fmt.Fprintf(w, "Method %s not allowed, POST required.", r.Method)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK" + lf))
+		quitOnce.Do(func() { close(quitquitquit) })
 	case "/server_info":
 		v := version.LastKnownEnvoy.String()
 		if os.Getenv("ENVOY_VERSION") == "dev" {
@@ -0,0 +1,42 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+// Channel is a symbolic version, which selects a release each time it is resolved, like a MinorVersion.
+type Channel string
+
+const (
+	// Latest is the newest non-debug release. This is what the first "run" uses.
+	Latest = Channel("latest")
+	// Stable is the newest patch of the minor version before the latest one, which has had more fixes.
+	Stable = Channel("stable")
+)
+
+// String satisfies Version.String
+func (c Channel) String() string {
+	return string(c)
+}
+
+// ToMinor satisfies Version.ToMinor. It is empty, as the minor version isn't known until the Channel is resolved.
+func (c Channel) ToMinor() MinorVersion {
+	return ""
+}
+
+// FindChannelVersion finds the version the Channel selects or empty if there is none.
+func FindChannelVersion(patchVersions []PatchVersion, c Channel) PatchVersion {
+	latest := FindLatestVersion(patchVersions)
+	if c == Latest || latest == "" {
+		return latest
+	}
+
+	// Stable is the latest version of those before the latest minor version.
+	latestParsed, _ := latest.Parse()
+	var previous []PatchVersion
+	for _, v := range patchVersions {
+		if p, ok := v.Parse(); ok && p.CompareMinor(latestParsed) < 0 {
+			previous = append(previous, v)
+		}
+	}
+	return FindLatestVersion(previous)
+}
@@ -0,0 +1,56 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindChannelVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		versions       []PatchVersion
+		expected       PatchVersion
+		expectedStable PatchVersion
+	}{
+		{
+			name:           "empty",
+			expected:       "",
+			expectedStable: "",
+		},
+		{
+			name:           "one minor",
+			versions:       []PatchVersion{"1.31.1", "1.31.2"},
+			expected:       "1.31.2",
+			expectedStable: "",
+		},
+		{
+			name:           "several minors",
+			versions:       []PatchVersion{"1.29.9", "1.30.1", "1.30.10", "1.30.2", "1.31.0", "1.31.1"},
+			expected:       "1.31.1",
+			expectedStable: "1.30.10",
+		},
+		{
+			name:           "mixed digit minors",
+			versions:       []PatchVersion{"1.8.5", "1.9.10", "1.9.2", "1.10.0"},
+			expected:       "1.10.0",
+			expectedStable: "1.9.10",
+		},
+		{
+			name:           "skips debug and dev",
+			versions:       []PatchVersion{"1.30.1", "1.30.2_debug", "1.31.0", "1.32.0_debug", Dev},
+			expected:       "1.31.0",
+			expectedStable: "1.30.1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, FindChannelVersion(tc.versions, Latest))
+			require.Equal(t, tc.expectedStable, FindChannelVersion(tc.versions, Stable))
+		})
+	}
+}
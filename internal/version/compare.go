@@ -0,0 +1,76 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// ParsedVersion is a PatchVersion as numbers, so that versions order correctly regardless of how many digits each
+// part has. Ex. "1.9.0" is before "1.10.0", and "9.0.0" is before "10.0.0".
+type ParsedVersion struct {
+	Major, Minor, Patch int
+	// Debug is true for a debug build, which orders after the release with the same numbers.
+	Debug bool
+}
+
+// Parse returns the numbers of v, or false if v isn't a release, such as "dev".
+func (v PatchVersion) Parse() (ParsedVersion, bool) {
+	matched := versionPattern.FindStringSubmatch(v.String())
+	if matched == nil || matched[2] == "" {
+		return ParsedVersion{}, false
+	}
+	major, minor, _ := strings.Cut(matched[1], ".")
+	var result ParsedVersion
+	result.Major, _ = strconv.Atoi(major)          //nolint:errcheck // versionPattern guarantees digits
+	result.Minor, _ = strconv.Atoi(minor)          //nolint:errcheck // versionPattern guarantees digits
+	result.Patch, _ = strconv.Atoi(matched[2][1:]) //nolint:errcheck // versionPattern guarantees digits
+	result.Debug = matched[3] != ""
+	return result, true
+}
+
+// Compare returns a negative number when p is before o, zero when they are the same and a positive number otherwise.
+func (p ParsedVersion) Compare(o ParsedVersion) int {
+	if c := p.CompareMinor(o); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(p.Patch, o.Patch); c != 0 {
+		return c
+	}
+	switch {
+	case p.Debug == o.Debug:
+		return 0
+	case p.Debug:
+		return 1
+	default:
+		return -1
+	}
+}
+
+// CompareMinor is like Compare, except it only compares the major and minor numbers.
+func (p ParsedVersion) CompareMinor(o ParsedVersion) int {
+	if c := cmp.Compare(p.Major, o.Major); c != 0 {
+		return c
+	}
+	return cmp.Compare(p.Minor, o.Minor)
+}
+
+// ComparePatchVersions is like ParsedVersion.Compare, except it accepts any PatchVersion, for use with functions like
+// slices.SortFunc. Versions that aren't releases, such as "dev", order before releases and by name.
+func ComparePatchVersions(a, b PatchVersion) int {
+	pa, aOk := a.Parse()
+	pb, bOk := b.Parse()
+	switch {
+	case aOk && bOk:
+		return pa.Compare(pb)
+	case aOk:
+		return 1
+	case bOk:
+		return -1
+	default:
+		return strings.Compare(a.String(), b.String())
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchVersion_Parse(t *testing.T) {
+	tests := []struct {
+		input      PatchVersion
+		expected   ParsedVersion
+		expectedOk bool
+	}{
+		{input: "1.18.3", expected: ParsedVersion{Major: 1, Minor: 18, Patch: 3}, expectedOk: true},
+		{input: "1.9.10_debug", expected: ParsedVersion{Major: 1, Minor: 9, Patch: 10, Debug: true}, expectedOk: true},
+		{input: "10.0.0", expected: ParsedVersion{Major: 10}, expectedOk: true},
+		{input: "1.18"},
+		{input: Dev},
+		{input: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input.String(), func(t *testing.T) {
+			actual, ok := tc.input.Parse()
+			require.Equal(t, tc.expected, actual)
+			require.Equal(t, tc.expectedOk, ok)
+		})
+	}
+}
+
+func TestComparePatchVersions(t *testing.T) {
+	// Each version is after the ones before it. Lexicographically, many would be out of order.
+	ordered := []PatchVersion{
+		Dev,
+		"1.9.2",
+		"1.9.10",
+		"1.9.10_debug",
+		"1.10.0",
+		"1.10.1",
+		"2.0.0",
+		"9.99.99",
+		"10.0.0",
+	}
+
+	for i, a := range ordered {
+		for j, b := range ordered {
+			require.Equal(t, cmp.Compare(i, j), sign(ComparePatchVersions(a, b)), "%s vs %s", a, b)
+		}
+	}
+
+	shuffled := []PatchVersion{"10.0.0", "1.10.0", "1.9.10_debug", "2.0.0", Dev, "1.9.2", "9.99.99", "1.10.1", "1.9.10"}
+	slices.SortFunc(shuffled, ComparePatchVersions)
+	require.Equal(t, ordered, shuffled)
+}
+
+// sign normalizes a comparison result to -1, 0 or 1.
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	default:
+		return 0
+	}
+}
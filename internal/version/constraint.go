@@ -0,0 +1,130 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// constraintOperators are the first characters of a Constraint, which no other Version starts with.
+const constraintOperators = "<>=~^"
+
+// comparatorPattern matches one condition of a Constraint. Ex. ">=1.30", "<1.32.4" or "~1.31"
+var comparatorPattern = regexp.MustCompile(`^(>=|<=|>|<|=|~|\^)([1-9]\d*)\.(\d+)(?:\.(\d+))?$`)
+
+// Constraint is a space-separated list of conditions on a PatchVersion, which must all be met. The latest release
+// that meets them is used, like a MinorVersion. Ex. ">=1.30 <1.32", "~1.31" or "^1.30"
+//
+// The operators are like npm's: "~1.31" means ">=1.31.0 <1.32.0", and "^1.30" means ">=1.30.0 <2.0.0". Without a
+// patch, ">1.30" means ">=1.31.0" and "<=1.31" means "<1.32.0". Debug versions never meet a Constraint.
+type Constraint string
+
+// NewConstraint returns a Constraint for a valid input like ">=1.30 <1.32" or empty if invalid.
+func NewConstraint(input string) Constraint {
+	if _, ok := parseConstraint(input); ok {
+		return Constraint(input)
+	}
+	return ""
+}
+
+// String satisfies Version.String
+func (c Constraint) String() string {
+	return string(c)
+}
+
+// ToMinor satisfies Version.ToMinor. It is empty, as a Constraint may be met by several minor versions.
+func (c Constraint) ToMinor() MinorVersion {
+	return ""
+}
+
+// Check returns true if v meets every condition of the Constraint.
+func (c Constraint) Check(v PatchVersion) bool {
+	comparators, ok := parseConstraint(string(c))
+	if !ok {
+		return false
+	}
+	pv, ok := v.Parse()
+	if !ok || pv.Debug {
+		return false // such as "dev" or "1.31.2_debug"
+	}
+	for _, cmp := range comparators {
+		if !cmp.check(pv) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindLatestMatchingVersion finds the latest version that meets the Constraint or empty if none do.
+func FindLatestMatchingVersion(patchVersions []PatchVersion, c Constraint) PatchVersion {
+	var matching []PatchVersion
+	for _, v := range patchVersions {
+		if c.Check(v) {
+			matching = append(matching, v)
+		}
+	}
+	return FindLatestVersion(matching)
+}
+
+// comparator is one condition of a Constraint.
+type comparator struct {
+	op string
+	v  ParsedVersion
+	// hasPatch is false when the patch is omitted, such as "1.31", in which case v has patch zero.
+	hasPatch bool
+}
+
+func (c comparator) check(v ParsedVersion) bool {
+	nextMinor := ParsedVersion{Major: c.v.Major, Minor: c.v.Minor + 1}
+	switch c.op {
+	case "=":
+		if c.hasPatch {
+			return v.Compare(c.v) == 0
+		}
+		return v.Compare(c.v) >= 0 && v.Compare(nextMinor) < 0
+	case ">":
+		if c.hasPatch {
+			return v.Compare(c.v) > 0
+		}
+		return v.Compare(nextMinor) >= 0
+	case ">=":
+		return v.Compare(c.v) >= 0
+	case "<":
+		return v.Compare(c.v) < 0
+	case "<=":
+		if c.hasPatch {
+			return v.Compare(c.v) <= 0
+		}
+		return v.Compare(nextMinor) < 0
+	case "~":
+		return v.Compare(c.v) >= 0 && v.Compare(nextMinor) < 0
+	default: // "^"
+		return v.Compare(c.v) >= 0 && v.Compare(ParsedVersion{Major: c.v.Major + 1}) < 0
+	}
+}
+
+// parseConstraint returns the conditions of input, or false if input isn't a valid Constraint.
+func parseConstraint(input string) ([]comparator, bool) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return nil, false
+	}
+	result := make([]comparator, 0, len(fields))
+	for _, f := range fields {
+		m := comparatorPattern.FindStringSubmatch(f)
+		if m == nil {
+			return nil, false
+		}
+		c := comparator{op: m[1], hasPatch: m[4] != ""}
+		c.v.Major, _ = strconv.Atoi(m[2]) //nolint:errcheck // comparatorPattern guarantees digits
+		c.v.Minor, _ = strconv.Atoi(m[3]) //nolint:errcheck // comparatorPattern guarantees digits
+		if c.hasPatch {
+			c.v.Patch, _ = strconv.Atoi(m[4]) //nolint:errcheck // comparatorPattern guarantees digits
+		}
+		result = append(result, c)
+	}
+	return result, true
+}
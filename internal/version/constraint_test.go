@@ -0,0 +1,88 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConstraint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Constraint
+	}{
+		{input: ">=1.30 <1.32", expected: ">=1.30 <1.32"},
+		{input: "~1.31", expected: "~1.31"},
+		{input: "^1.30.2", expected: "^1.30.2"},
+		{input: "=1.31.2", expected: "=1.31.2"},
+		{input: " >1.30  <=1.31 ", expected: " >1.30  <=1.31 "},
+		{input: ""},
+		{input: "1.31"},
+		{input: ">=1"},
+		{input: "=>1.30"},
+		{input: ">=1.30 || <1.20"},
+		{input: "~1.31_debug"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, NewConstraint(tc.input))
+		})
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	tests := []struct {
+		constraint Constraint
+		matches    []PatchVersion
+		misses     []PatchVersion
+	}{
+		{constraint: ">=1.30 <1.32", matches: []PatchVersion{"1.30.0", "1.31.9"}, misses: []PatchVersion{"1.29.9", "1.32.0"}},
+		{constraint: "~1.31", matches: []PatchVersion{"1.31.0", "1.31.10"}, misses: []PatchVersion{"1.30.9", "1.32.0"}},
+		{constraint: "~1.31.2", matches: []PatchVersion{"1.31.2", "1.31.10"}, misses: []PatchVersion{"1.31.1", "1.32.0"}},
+		{constraint: "^1.30", matches: []PatchVersion{"1.30.0", "1.38.1"}, misses: []PatchVersion{"1.29.9", "2.0.0"}},
+		{constraint: "^1.30.2", matches: []PatchVersion{"1.30.2", "1.99.0"}, misses: []PatchVersion{"1.30.1", "2.0.0"}},
+		{constraint: "=1.31", matches: []PatchVersion{"1.31.0", "1.31.5"}, misses: []PatchVersion{"1.30.5", "1.32.0"}},
+		{constraint: "=1.31.2", matches: []PatchVersion{"1.31.2"}, misses: []PatchVersion{"1.31.3"}},
+		{constraint: ">1.30", matches: []PatchVersion{"1.31.0"}, misses: []PatchVersion{"1.30.9"}},
+		{constraint: ">1.30.2", matches: []PatchVersion{"1.30.3"}, misses: []PatchVersion{"1.30.2"}},
+		{constraint: "<=1.31", matches: []PatchVersion{"1.31.9"}, misses: []PatchVersion{"1.32.0"}},
+		{constraint: "<=1.31.2", matches: []PatchVersion{"1.31.2"}, misses: []PatchVersion{"1.31.3"}},
+		{constraint: ">=1.30", misses: []PatchVersion{"1.31.2_debug", Dev, DevLatest}},
+		{constraint: ">=1.30 <x", misses: []PatchVersion{"1.31.2"}}, // invalid
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.constraint.String(), func(t *testing.T) {
+			for _, v := range tc.matches {
+				require.True(t, tc.constraint.Check(v), v)
+			}
+			for _, v := range tc.misses {
+				require.False(t, tc.constraint.Check(v), v)
+			}
+		})
+	}
+}
+
+func TestFindLatestMatchingVersion(t *testing.T) {
+	versions := []PatchVersion{"1.29.9", "1.30.10", "1.30.9", "1.31.2", "1.31.3_debug", "1.32.0"}
+
+	tests := []struct {
+		constraint Constraint
+		expected   PatchVersion
+	}{
+		{constraint: ">=1.30 <1.32", expected: "1.31.2"},
+		{constraint: "~1.30", expected: "1.30.10"},
+		{constraint: "^1.30", expected: "1.32.0"},
+		{constraint: ">=1.33"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.constraint.String(), func(t *testing.T) {
+			require.Equal(t, tc.expected, FindLatestMatchingVersion(versions, tc.constraint))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"strings"
+	"time"
+)
+
+// SupportMonths is how long Envoy supports a minor version with security fixes, after its first release.
+// See https://github.com/envoyproxy/envoy/blob/main/RELEASES.md#release-schedule
+const SupportMonths = 12
+
+// releaseDateLayout is the format of a ReleaseDate.
+const releaseDateLayout = "2006-01-02"
+
+// SupportEnd returns the date Envoy stops supporting the minor version of v, or false if it isn't known, such as
+// for "dev" or a version missing from Versions. Debug versions have the same support as their release.
+func (r *ReleaseVersions) SupportEnd(v PatchVersion) (ReleaseDate, bool) {
+	minor := PatchVersion(strings.TrimSuffix(v.String(), debugSuffix)).ToMinor()
+	if minor == "" {
+		return "", false
+	}
+
+	// The support window starts with the first patch of the minor version, which is usually ".0".
+	var first time.Time
+	for pv, release := range r.Versions {
+		if pv.ToMinor() != minor {
+			continue
+		}
+		if d, err := time.Parse(releaseDateLayout, string(release.ReleaseDate)); err == nil && (first.IsZero() || d.Before(first)) {
+			first = d
+		}
+	}
+	if first.IsZero() {
+		return "", false
+	}
+	return ReleaseDate(first.AddDate(0, SupportMonths, 0).Format(releaseDateLayout)), true
+}
+
+// IsSupported returns false if support for v ended before now. Versions with an unknown SupportEnd are supported.
+func (r *ReleaseVersions) IsSupported(v PatchVersion, now time.Time) bool {
+	end, ok := r.SupportEnd(v)
+	return !ok || now.Format(releaseDateLayout) < string(end)
+}
@@ -0,0 +1,51 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseVersions_SupportEnd(t *testing.T) {
+	rv := &ReleaseVersions{Versions: map[PatchVersion]Release{
+		"1.30.0":       {ReleaseDate: "2024-04-16"},
+		"1.30.4":       {ReleaseDate: "2024-07-10"},
+		"1.30.4_debug": {ReleaseDate: "2024-07-10"},
+		"1.31.2":       {ReleaseDate: "2024-09-19"}, // the first patches may be missing
+		"1.32.0":       {ReleaseDate: "not a date"},
+	}}
+
+	tests := []struct {
+		version    PatchVersion
+		expected   ReleaseDate
+		expectedOk bool
+	}{
+		{version: "1.30.4", expected: "2025-04-16", expectedOk: true},
+		{version: "1.30.4_debug", expected: "2025-04-16", expectedOk: true},
+		{version: "1.30.9", expected: "2025-04-16", expectedOk: true},
+		{version: "1.31.2", expected: "2025-09-19", expectedOk: true},
+		{version: "1.32.0"},
+		{version: "1.33.0"},
+		{version: Dev},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version.String(), func(t *testing.T) {
+			end, ok := rv.SupportEnd(tc.version)
+			require.Equal(t, tc.expected, end)
+			require.Equal(t, tc.expectedOk, ok)
+		})
+	}
+}
+
+func TestReleaseVersions_IsSupported(t *testing.T) {
+	rv := &ReleaseVersions{Versions: map[PatchVersion]Release{"1.30.0": {ReleaseDate: "2024-04-16"}}}
+
+	require.True(t, rv.IsSupported("1.30.0", time.Date(2025, 4, 15, 0, 0, 0, 0, time.UTC)))
+	require.False(t, rv.IsSupported("1.30.0", time.Date(2025, 4, 16, 0, 0, 0, 0, time.UTC)))
+	require.True(t, rv.IsSupported("1.31.0", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)), "unknown is supported")
+}
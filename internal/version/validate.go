@@ -0,0 +1,70 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+)
+
+var (
+	platformPattern  = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+$`)
+	sha256SumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	// imagePattern is a loose form of an OCI image reference: a repository, optionally prefixed by a registry, then
+	// an optional tag and digest.
+	imagePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:-]*(?:/[a-z0-9][a-z0-9._-]*)*(?::\w[\w.-]{0,127})?(?:@sha256:[0-9a-f]{64})?$`)
+)
+
+// Validate returns an error for each problem in the Envoy versions JSON, such as a tarball without a sha256sum, or
+// nil if there are none. Each error starts with the JSON path of the problem. Ex. `versions["1.18.3"].releaseDate`
+func (r *ReleaseVersions) Validate() error {
+	var errs []error
+	for _, v := range slices.Sorted(maps.Keys(r.Versions)) {
+		p := fmt.Sprintf("versions[%q]", v)
+		if NewPatchVersion(string(v)) == "" {
+			errs = append(errs, fmt.Errorf("%s: invalid version: should look like %q", p, LastKnownEnvoy))
+		}
+		release := r.Versions[v]
+		if release.Image != "" && NewImage(string(release.Image)) == "" {
+			errs = append(errs, fmt.Errorf("%s.image: invalid image %q: should look like \"envoyproxy/envoy:v%s\"", p, release.Image, v))
+		}
+		errs = append(errs, validateRelease(p, release.ReleaseDate, release.Tarballs, r.SHA256Sums)...)
+	}
+	if r.Dev != nil {
+		errs = append(errs, validateRelease("dev", r.Dev.ReleaseDate, r.Dev.Tarballs, r.SHA256Sums)...)
+	}
+	for _, t := range slices.Sorted(maps.Keys(r.SHA256Sums)) {
+		if s := r.SHA256Sums[t]; !sha256SumPattern.MatchString(string(s)) {
+			errs = append(errs, fmt.Errorf("sha256sums[%q]: invalid SHA-256 sum %q: should be 64 lowercase hex characters", t, s))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateRelease(p string, releaseDate ReleaseDate, tarballs map[Platform]TarballURL, sha256Sums map[Tarball]SHA256Sum) []error {
+	var errs []error
+	if _, err := time.Parse("2006-01-02", string(releaseDate)); err != nil {
+		errs = append(errs, fmt.Errorf("%s.releaseDate: invalid date %q: should look like \"2006-01-02\"", p, releaseDate))
+	}
+	for _, platform := range slices.Sorted(maps.Keys(tarballs)) {
+		tp := fmt.Sprintf("%s.tarballs[%q]", p, platform)
+		if !platformPattern.MatchString(string(platform)) {
+			errs = append(errs, fmt.Errorf("%s: invalid platform: should look like \"linux/amd64\"", tp))
+		}
+		u, err := url.Parse(string(tarballs[platform]))
+		if err != nil || u.Path == "" {
+			errs = append(errs, fmt.Errorf("%s: invalid tarball URL %q", tp, tarballs[platform]))
+			continue
+		}
+		if tarball := tarballs[platform].Tarball(); sha256Sums[tarball] == "" {
+			errs = append(errs, fmt.Errorf("%s: missing sha256sums[%q]", tp, tarball))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,94 @@
+// Copyright func-e contributors
+// SPDX-License-Identifier: Apache-2.0
+
+package version
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseVersions_Validate(t *testing.T) {
+	sha256Sum := strings.Repeat("a", 64)
+
+	tests := []struct {
+		name        string
+		data        string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			data: `{"versions":{"1.31.2":{"releaseDate":"2024-09-19","tarballs":{"linux/amd64":"https://example.com/envoy-v1.31.2-linux-amd64.tar.xz"}}},` +
+				`"sha256sums":{"envoy-v1.31.2-linux-amd64.tar.xz":"` + sha256Sum + `","envoy-dev-linux-arm64.tar.xz":"` + sha256Sum + `"},` +
+				`"dev":{"releaseDate":"2025-01-20","commitSha":"92c6cb58","tarballs":{"linux/arm64":"https://example.com/envoy-dev-linux-arm64.tar.xz"}}}`,
+		},
+		{
+			name: "valid relative URL",
+			data: `{"versions":{"1.31.2":{"releaseDate":"2024-09-19","tarballs":{"linux/amd64":"1.31.2/envoy-v1.31.2-linux-amd64.tar.xz"}}},` +
+				`"sha256sums":{"envoy-v1.31.2-linux-amd64.tar.xz":"` + sha256Sum + `"}}`,
+		},
+		{
+			name: "valid image",
+			data: `{"versions":{"1.31.2":{"releaseDate":"2024-09-19","image":"registry.example.com:5000/envoyproxy/envoy:v1.31.2@sha256:` + sha256Sum + `"}}}`,
+		},
+		{
+			name:        "invalid image",
+			data:        `{"versions":{"1.31.2":{"releaseDate":"2024-09-19","image":"Envoy Proxy"}}}`,
+			expectedErr: `versions["1.31.2"].image: invalid image "Envoy Proxy": should look like "envoyproxy/envoy:v1.31.2"`,
+		},
+		{
+			name:        "invalid version",
+			data:        `{"versions":{"v1.31.2":{"releaseDate":"2024-09-19"}}}`,
+			expectedErr: `versions["v1.31.2"]: invalid version: should look like "` + LastKnownEnvoy.String() + `"`,
+		},
+		{
+			name:        "invalid releaseDate",
+			data:        `{"versions":{"1.31.2":{"releaseDate":"19/09/2024"}}}`,
+			expectedErr: `versions["1.31.2"].releaseDate: invalid date "19/09/2024": should look like "2006-01-02"`,
+		},
+		{
+			name:        "invalid platform",
+			data:        `{"versions":{"1.31.2":{"releaseDate":"2024-09-19","tarballs":{"Linux-AMD64":"envoy.tar.xz"}}},"sha256sums":{"envoy.tar.xz":"` + sha256Sum + `"}}`,
+			expectedErr: `versions["1.31.2"].tarballs["Linux-AMD64"]: invalid platform: should look like "linux/amd64"`,
+		},
+		{
+			name:        "invalid tarball URL",
+			data:        `{"versions":{"1.31.2":{"releaseDate":"2024-09-19","tarballs":{"linux/amd64":"https://example.com"}}}}`,
+			expectedErr: `versions["1.31.2"].tarballs["linux/amd64"]: invalid tarball URL "https://example.com"`,
+		},
+		{
+			name:        "dev without sha256sum",
+			data:        `{"versions":{},"dev":{"releaseDate":"2025-01-20","commitSha":"92c6cb58","tarballs":{"linux/arm64":"dev/envoy-dev-linux-arm64.tar.xz"}}}`,
+			expectedErr: `dev.tarballs["linux/arm64"]: missing sha256sums["envoy-dev-linux-arm64.tar.xz"]`,
+		},
+		{
+			name:        "invalid sha256sum",
+			data:        `{"versions":{},"sha256sums":{"envoy.tar.xz":"ABC"}}`,
+			expectedErr: `sha256sums["envoy.tar.xz"]: invalid SHA-256 sum "ABC": should be 64 lowercase hex characters`,
+		},
+		{
+			name: "reports every problem",
+			data: `{"versions":{"1.31.2":{"releaseDate":"","tarballs":{"linux/amd64":"a.tar.xz","darwin/arm64":"b.tar.xz"}}},"sha256sums":{"b.tar.xz":"b"}}`,
+			expectedErr: `versions["1.31.2"].releaseDate: invalid date "": should look like "2006-01-02"
+versions["1.31.2"].tarballs["linux/amd64"]: missing sha256sums["a.tar.xz"]
+sha256sums["b.tar.xz"]: invalid SHA-256 sum "b": should be 64 lowercase hex characters`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var rv ReleaseVersions
+			require.NoError(t, json.Unmarshal([]byte(tc.data), &rv))
+
+			err := rv.Validate()
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
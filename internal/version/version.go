@@ -8,8 +8,9 @@ import (
 	"context"
 	_ "embed" // We embed the Envoy version so that we can cache it in CI
 	"fmt"
+	"net/url"
+	"path"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -33,8 +34,8 @@ var (
 // debugSuffix is used to implement PatchVersion.ToMinor
 const debugSuffix = "_debug"
 
-// Version is a union type that allows commands to operate regardless of whether the input is a MinorVersion or a
-// PatchVersion.
+// Version is a union type that allows commands to operate regardless of whether the input is a MinorVersion, a
+// PatchVersion, a Constraint or a Channel.
 type Version interface {
 	// String allows access to the underlying representation. Ex. "1.18", "1.18_debug", "1.19.3_debug"
 	String() string
@@ -83,12 +84,21 @@ func NewVersion(tag, input string) (Version, error) {
 	if input == "dev-latest" {
 		return DevLatest, nil
 	}
+	if c := Channel(input); c == Latest || c == Stable {
+		return c, nil
+	}
 	if pv := NewPatchVersion(input); pv != "" {
 		return pv, nil
 	}
 	if mv := NewMinorVersion(input); mv != "" {
 		return mv, nil
 	}
+	if strings.ContainsAny(input[:1], constraintOperators) {
+		if c := NewConstraint(input); c != "" {
+			return c, nil
+		}
+		return nil, fmt.Errorf(`invalid %s: %q should look like ">=1.30 <1.32", "~1.31" or "^1.30"`, tag, input)
+	}
 	return nil, fmt.Errorf("invalid %s: %q should look like %q or %q", tag, input, LastKnownEnvoy, LastKnownEnvoy.ToMinor())
 }
 
@@ -119,25 +129,19 @@ func (v PatchVersion) ToMinor() MinorVersion {
 // Patch attempts to parse a Patch number from the Version.String.
 // This will always succeed when created via NewVersion or NewPatchVersion
 func (v PatchVersion) Patch() int {
-	matched := versionPattern.FindStringSubmatch(v.String())
-	if matched == nil {
-		return 0 // impossible if created via NewVersion or NewPatchVersion
-	}
-	i, _ := strconv.Atoi(matched[2][1:]) //nolint:errcheck // versionPattern guarantees the suffix is numeric
-	return i
+	p, _ := v.Parse()
+	return p.Patch // zero is impossible if created via NewVersion or NewPatchVersion
 }
 
 // FindLatestPatchVersion finds the latest Patch version for the given minor version or empty if not found.
 func FindLatestPatchVersion(patchVersions []PatchVersion, minorVersion MinorVersion) PatchVersion {
 	var latestVersion PatchVersion
-	var latestPatch int
 	for _, v := range patchVersions {
 		if v.ToMinor() != minorVersion {
 			continue
 		}
 
-		if p := v.Patch(); p >= latestPatch {
-			latestPatch = p
+		if latestVersion == "" || ComparePatchVersions(v, latestVersion) > 0 {
 			latestVersion = v
 		}
 	}
@@ -148,14 +152,11 @@ func FindLatestPatchVersion(patchVersions []PatchVersion, minorVersion MinorVers
 func FindLatestVersion(patchVersions []PatchVersion) PatchVersion {
 	var latestVersion PatchVersion
 	for _, v := range patchVersions {
-		if strings.HasSuffix(v.String(), debugSuffix) {
-			continue
+		if p, ok := v.Parse(); !ok || p.Debug {
+			continue // such as "dev" or "1.18.3_debug"
 		}
 
-		// Until Envoy 2.0.0, minor versions are double-digit and can be lexicographically compared.
-		// Patches have to be numerically compared, as they can be single or double-digit (albeit unlikely).
-		if m := v.ToMinor(); m > latestVersion.ToMinor() ||
-			m == latestVersion.ToMinor() && v.Patch() > latestVersion.Patch() {
+		if latestVersion == "" || ComparePatchVersions(v, latestVersion) > 0 {
 			latestVersion = v
 		}
 	}
@@ -192,6 +193,28 @@ type Tarball string
 // TarballURL is the HTTPS URL to the Tarball. SHA256Sums must include its base name.
 type TarballURL string
 
+// Tarball returns the base name of the URL path, which is the key of its SHA-256 sum in SHA256Sums. This ignores any
+// query, such as for a signed URL.
+func (u TarballURL) Tarball() Tarball {
+	p := string(u)
+	if parsed, err := url.Parse(p); err == nil {
+		p = parsed.Path
+	}
+	return Tarball(path.Base(p))
+}
+
+// Image is a reference to an OCI image with Envoy at "/usr/local/bin/envoy". It is usually an image index, which has
+// a manifest for each platform. Ex. "envoyproxy/envoy:v1.18.3" or "registry.example.com/envoy@sha256:..."
+type Image string
+
+// NewImage returns an Image for a valid input like "envoyproxy/envoy:v1.18.3" or empty if invalid.
+func NewImage(input string) Image {
+	if imagePattern.MatchString(input) {
+		return Image(input)
+	}
+	return ""
+}
+
 // SHA256Sum is a SHA-256 lower-hex hash. Ex. "1274f55b3022bc1331aed41089f189094e00729981fe132ce00aac6272ea0770"
 type SHA256Sum string
 
@@ -200,8 +223,20 @@ type ReleaseDate string
 
 // Release primarily maps available Tarballs for a Version
 type Release struct {
-	ReleaseDate ReleaseDate
+	ReleaseDate ReleaseDate `json:"releaseDate"`
 
 	// Tarballs are the Tarballs available by Platform
 	Tarballs map[Platform]TarballURL `json:"tarballs,omitempty"`
+
+	// Image is pulled for a Platform not in Tarballs, if set.
+	Image Image `json:"image,omitempty"`
+}
+
+// HasPlatform returns true if the release has a tarball for p, or an image and p is Linux. The image index isn't
+// pulled to check, but container images are Linux.
+func (r Release) HasPlatform(p Platform) bool {
+	if _, ok := r.Tarballs[p]; ok {
+		return true
+	}
+	return r.Image != "" && strings.HasPrefix(string(p), "linux/")
 }
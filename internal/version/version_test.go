@@ -94,6 +94,22 @@ func TestNewVersion(t *testing.T) {
 			input:       "a.b.c",
 			expectedErr: fmt.Sprintf(`invalid [version] argument: "a.b.c" should look like %q or %q`, LastKnownEnvoy, LastKnownEnvoyMinor),
 		},
+		{
+			input:    ">=1.30 <1.32",
+			expected: Constraint(">=1.30 <1.32"),
+		},
+		{
+			input:    "latest",
+			expected: Latest,
+		},
+		{
+			input:    "stable",
+			expected: Stable,
+		},
+		{
+			input:       ">=1.30 <1.x",
+			expectedErr: `invalid [version] argument: ">=1.30 <1.x" should look like ">=1.30 <1.32", "~1.31" or "^1.30"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -233,6 +249,47 @@ func TestPatchVersion_Patch(t *testing.T) {
 	}
 }
 
+func TestRelease_HasPlatform(t *testing.T) {
+	tarball := Release{Tarballs: map[Platform]TarballURL{"darwin/arm64": "envoy.tar.xz"}}
+	image := Release{Image: "envoyproxy/envoy:v1.31.2"}
+
+	tests := []struct {
+		name     string
+		release  Release
+		platform Platform
+		expected bool
+	}{
+		{name: "tarball", release: tarball, platform: "darwin/arm64", expected: true},
+		{name: "tarball other platform", release: tarball, platform: "linux/arm64"},
+		{name: "image linux", release: image, platform: "linux/arm64", expected: true},
+		{name: "image darwin", release: image, platform: "darwin/arm64"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.release.HasPlatform(tc.platform))
+		})
+	}
+}
+
+func TestTarballURL_Tarball(t *testing.T) {
+	tests := []struct {
+		input    TarballURL
+		expected Tarball
+	}{
+		{input: "https://archive.tetratelabs.io/envoy/download/v1.31.2/envoy-v1.31.2-linux-amd64.tar.xz", expected: "envoy-v1.31.2-linux-amd64.tar.xz"},
+		{input: "https://example.com/envoy-v1.31.2-linux-amd64.tar.xz?X-Amz-Signature=abc", expected: "envoy-v1.31.2-linux-amd64.tar.xz"},
+		{input: "file:///mirror/envoy-dev-linux-amd64.tar.gz", expected: "envoy-dev-linux-amd64.tar.gz"},
+		{input: "envoy.tar.xz", expected: "envoy.tar.xz"},
+	}
+
+	for _, tc := range tests {
+		t.Run(string(tc.input), func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.input.Tarball())
+		})
+	}
+}
+
 func TestVersion_String(t *testing.T) {
 	tests := []struct {
 		input    Version
@@ -332,6 +389,17 @@ func TestFindLatestPatch(t *testing.T) {
 			},
 			minorVersion: MinorVersion("1.1"),
 		},
+		{
+			name: "mixed digit minor",
+			patchVersions: []PatchVersion{
+				PatchVersion("1.9.10"),
+				PatchVersion("1.9.9"),
+				PatchVersion("1.10.1"),
+				PatchVersion("1.1.20"),
+			},
+			minorVersion: MinorVersion("1.9"),
+			expected:     PatchVersion("1.9.10"),
+		},
 		{
 			name: "debug",
 			patchVersions: []PatchVersion{
@@ -396,6 +464,32 @@ func TestFindLatestVersion(t *testing.T) {
 			},
 			expected: PatchVersion("1.20.1"),
 		},
+		{
+			name: "mixed digit minor",
+			patchVersions: []PatchVersion{
+				PatchVersion("1.9.3"),
+				PatchVersion("1.10.1"),
+				PatchVersion("1.9.12"),
+			},
+			expected: PatchVersion("1.10.1"),
+		},
+		{
+			name: "double digit major",
+			patchVersions: []PatchVersion{
+				PatchVersion("9.1.0"),
+				PatchVersion("10.0.0"),
+				PatchVersion("2.30.0"),
+			},
+			expected: PatchVersion("10.0.0"),
+		},
+		{
+			name: "ignores dev",
+			patchVersions: []PatchVersion{
+				Dev,
+				PatchVersion("1.20.1"),
+			},
+			expected: PatchVersion("1.20.1"),
+		},
 	}
 
 	for _, tt := range tests {